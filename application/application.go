@@ -1,14 +1,21 @@
 package application
 
 import (
+	"context"
+	"fmt"
 	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/errors"
 	"github.com/N-Vokhmyanin/go-framework/health"
 	"github.com/N-Vokhmyanin/go-framework/logger"
 	"github.com/N-Vokhmyanin/go-framework/logger/zap"
+	"github.com/N-Vokhmyanin/go-framework/utils/di"
 	"github.com/N-Vokhmyanin/go-framework/utils/rfl"
+	"github.com/namsral/flag"
 	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
 	"os"
 	"sort"
+	"strings"
 	"sync"
 )
 
@@ -17,11 +24,21 @@ type appInstance struct {
 	env  string
 
 	config    contracts.Config
-	container contracts.Container
+	container *container
 	providers []contracts.Provider
 	commands  []*cli.Command
 
 	loggerProvider logger.Provider
+	strict         bool
+	configValues   map[string]string
+	configFiles    []string
+	overrides      []interface{}
+
+	timeouts      lifecycleTimeouts
+	starting      bool
+	started       []interface{}
+	stopped       []interface{}
+	lifecycleLock sync.Mutex
 }
 
 var _ contracts.Application = (*appInstance)(nil)
@@ -48,6 +65,10 @@ func (a *appInstance) Env() string {
 	return a.env
 }
 
+func (a *appInstance) IsProduction() bool {
+	return a.env == contracts.EnvProduction
+}
+
 func (a *appInstance) Name() string {
 	return a.name
 }
@@ -64,10 +85,42 @@ func (a *appInstance) Transient(resolver interface{}) {
 	a.container.Transient(resolver)
 }
 
+func (a *appInstance) Scoped(resolver interface{}) {
+	a.container.Scoped(resolver)
+}
+
+func (a *appInstance) SingletonNamed(name string, resolver interface{}) {
+	a.container.SingletonNamed(name, resolver)
+}
+
+func (a *appInstance) TransientNamed(name string, resolver interface{}) {
+	a.container.TransientNamed(name, resolver)
+}
+
+func (a *appInstance) Tag(tag string, resolver interface{}) {
+	a.container.Tag(tag, resolver)
+}
+
 func (a *appInstance) Make(receiver interface{}) []interface{} {
 	return a.container.Make(receiver)
 }
 
+func (a *appInstance) MakeNamed(name string, receiver interface{}) {
+	a.container.MakeNamed(name, receiver)
+}
+
+func (a *appInstance) MakeTagged(tag string) []interface{} {
+	return a.container.MakeTagged(tag)
+}
+
+func (a *appInstance) Inject(target interface{}) {
+	a.container.Inject(target)
+}
+
+func (a *appInstance) NewScope() contracts.Scope {
+	return a.container.NewScope()
+}
+
 func (a *appInstance) Command(items ...*cli.Command) {
 	a.commands = append(a.commands, items...)
 }
@@ -77,66 +130,105 @@ func (a *appInstance) Provide(items ...contracts.Provider) {
 }
 
 func (a *appInstance) BootService() {
-	// Boot services
-	for _, instance := range a.Instances() {
-		if runnable, ok := instance.(contracts.CanBoot); ok && instance != a {
-			runnable.BootService()
-		}
+	if err := a.BootServices(context.Background()); err != nil {
+		panic(err)
 	}
 }
 
 func (a *appInstance) InitService() {
-	// Init services
-	for _, instance := range a.Instances() {
-		if runnable, ok := instance.(contracts.CanInit); ok && instance != a {
-			runnable.InitService()
-		}
+	if err := a.InitServices(context.Background()); err != nil {
+		panic(err)
 	}
 }
 
 func (a *appInstance) StartService() {
-	// Start services
-	for _, instance := range a.Instances() {
-		if runnable, ok := instance.(contracts.CanStart); ok && instance != a {
-			runnable.StartService()
-		}
+	if err := a.StartServices(context.Background()); err != nil {
+		panic(err)
 	}
 }
 
 func (a *appInstance) StopService() {
-	wg := sync.WaitGroup{}
-	defer wg.Wait()
-
-	// Shutdown services
-	for _, instance := range a.Instances() {
-		if runnable, ok := instance.(contracts.CanStop); ok && instance != a {
-			wg.Add(1)
-			go func(r contracts.CanStop) {
-				r.StopService()
-				wg.Done()
-			}(runnable)
-		}
+	if err := a.StopServices(context.Background()); err != nil {
+		di.Get[logger.Logger](a).Errorw("stop services failed", zap.Error(err))
 	}
 }
 
-func (a *appInstance) Run(args []string) {
+// Bootstrap configures, boots and registers providers and boots services without running commands,
+// the arguments are parsed as configuration flags.
+func (a *appInstance) Bootstrap(arguments []string) error {
+	// Order providers by their dependencies
+	providers, err := sortProviders(a.providers)
+	if err != nil {
+		return err
+	}
+	a.providers = providers
+
 	// Configure all providers
 	for _, provider := range a.providers {
 		providerKey := rfl.FullTypeName(provider)
 		provider.Config(a.config.New(providerKey))
 	}
-	a.config.Parse(os.Args[1:])
+	if len(a.configFiles) > 0 {
+		arguments = append([]string{"-" + flag.DefaultConfigFlagname + "=" + strings.Join(a.configFiles, ",")}, arguments...)
+	}
+	a.config.Parse(arguments)
+	for name, value := range a.configValues {
+		if err = a.config.Set(name, value); err != nil {
+			return err
+		}
+	}
+	if err = validateConfig(a.config, a.providers, a.env); err != nil {
+		return err
+	}
 
 	// Boot providers
 	for _, provider := range a.providers {
+		a.container.setProvider(rfl.FullTypeName(provider))
 		provider.Boot(a)
 	}
+	a.container.setProvider("override")
+	for _, resolver := range a.overrides {
+		a.container.override(resolver)
+	}
 	// Register providers
 	for _, provider := range a.providers {
+		a.container.setProvider(rfl.FullTypeName(provider))
 		provider.Register(a)
 	}
+	a.container.setProvider("")
 
-	a.BootService()
+	a.Make(func(log logger.Logger) {
+		for _, duplicate := range a.container.Duplicates() {
+			log.Warnw("binding registered more than once, previous binding is overwritten", "abstraction", duplicate)
+		}
+	})
+	if a.strict {
+		if err = a.container.Validate(); err != nil {
+			return errors.WrapWith(err, "container validation failed")
+		}
+	}
+
+	if err = a.BootServices(context.Background()); err != nil {
+		return errors.WrapWith(err, "boot services failed")
+	}
+	return nil
+}
+
+func (a *appInstance) Run(args []string) {
+	if len(args) == 0 {
+		args = os.Args
+	}
+	if err := a.Bootstrap(args[1:]); err != nil {
+		if errors.IsErr[*configValidationErr](err) {
+			// config is validated before providers boot, so the logger is not available yet
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if log, ok := di.Maybe[logger.Logger](a).Get(); ok {
+			log.Fatalw("bootstrap failed", zap.Error(err))
+		}
+		panic(err)
+	}
 	defer a.StopService()
 
 	// Register commands
@@ -148,7 +240,8 @@ func (a *appInstance) Run(args []string) {
 
 	a.Make(
 		func(log logger.Logger) {
-			if err := cliApp.Run(args); err != nil {
+			// configuration flags are consumed by the config
+			if err := cliApp.Run(append([]string{args[0]}, a.config.Args()...)); err != nil {
 				log.Error(err)
 			}
 		},
@@ -0,0 +1,166 @@
+package apptest
+
+import (
+	"context"
+	"github.com/N-Vokhmyanin/go-framework/application"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/transport"
+	"github.com/N-Vokhmyanin/go-framework/utils/di"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"net"
+	"testing"
+	"time"
+)
+
+// ephemeralPorts make the servers listen on ports chosen by the system.
+var ephemeralPorts = map[string]string{
+	"SERVER_GRPC_PORT": "0",
+	"SERVER_HTTP_PORT": "0",
+	"HEALTH_GRPC_PORT": "0",
+	"HEALTH_HTTP_PORT": "0",
+}
+
+type options struct {
+	config      map[string]string
+	overrides   []interface{}
+	appOptions  []application.Option
+	stopTimeout time.Duration
+}
+
+type Option func(o *options)
+
+// WithConfig sets configuration values, they take precedence over the environment.
+//
+//goland:noinspection GoUnusedExportedFunction
+func WithConfig(values map[string]string) Option {
+	return func(o *options) {
+		for name, value := range values {
+			o.config[name] = value
+		}
+	}
+}
+
+// WithOverride binds singleton resolvers replacing the bindings of providers.
+//
+//goland:noinspection GoUnusedExportedFunction
+func WithOverride(resolvers ...interface{}) Option {
+	return func(o *options) {
+		o.overrides = append(o.overrides, resolvers...)
+	}
+}
+
+//goland:noinspection GoUnusedExportedFunction
+func WithAppOptions(appOptions ...application.Option) Option {
+	return func(o *options) {
+		o.appOptions = append(o.appOptions, appOptions...)
+	}
+}
+
+//goland:noinspection GoUnusedExportedFunction
+func WithStopTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.stopTimeout = timeout
+	}
+}
+
+// App is an application booted for a test, services are stopped on the test cleanup.
+type App struct {
+	contracts.Application
+	t           testing.TB
+	stopTimeout time.Duration
+	conn        *grpc.ClientConn
+}
+
+// New configures, boots and registers the providers without parsing command line arguments.
+func New(t testing.TB, providers []contracts.Provider, opts ...Option) *App {
+	t.Helper()
+
+	o := &options{
+		config:      map[string]string{},
+		stopTimeout: 10 * time.Second,
+	}
+	for name, value := range ephemeralPorts {
+		o.config[name] = value
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	appOptions := append([]application.Option{
+		application.ConfigValuesOption(o.config),
+		application.OverrideOption(o.overrides...),
+	}, o.appOptions...)
+
+	a := &App{
+		Application: application.New(appOptions...),
+		t:           t,
+		stopTimeout: o.stopTimeout,
+	}
+	a.Provide(providers...)
+
+	if err := a.Bootstrap(nil); err != nil {
+		t.Fatalf("bootstrap application: %v", err)
+	}
+	t.Cleanup(a.stop)
+	return a
+}
+
+// Start initializes and starts services, they are shut down on the test cleanup.
+func (a *App) Start() *App {
+	a.t.Helper()
+
+	if err := a.InitServices(context.Background()); err != nil {
+		a.t.Fatalf("init services: %v", err)
+	}
+	if err := a.StartServices(context.Background()); err != nil {
+		a.t.Fatalf("start services: %v", err)
+	}
+	return a
+}
+
+// GrpcConn returns a client connection to the started grpc server.
+func (a *App) GrpcConn() *grpc.ClientConn {
+	a.t.Helper()
+
+	if a.conn == nil {
+		conn, err := grpc.NewClient(
+			localAddr(di.Get[transport.GrpcServer](a).Addr()),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		if err != nil {
+			a.t.Fatalf("dial grpc server: %v", err)
+		}
+		a.conn = conn
+	}
+	return a.conn
+}
+
+// HttpURL returns the base url of the started http gateway.
+func (a *App) HttpURL() string {
+	return "http://" + localAddr(di.Get[transport.HttpGateway](a).Addr())
+}
+
+func (a *App) stop() {
+	if a.conn != nil {
+		_ = a.conn.Close()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.stopTimeout)
+	defer cancel()
+	if err := a.StopServices(ctx); err != nil {
+		a.t.Errorf("stop services: %v", err)
+	}
+}
+
+// localAddr replaces an unspecified listening host with the loopback one.
+func localAddr(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port)
+}
@@ -0,0 +1,41 @@
+package apptest
+
+import (
+	"context"
+	"github.com/N-Vokhmyanin/go-framework/application"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/transport"
+	"github.com/N-Vokhmyanin/go-framework/utils/di"
+	"google.golang.org/grpc/codes"
+	grpcHealthV1 "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"testing"
+)
+
+func Test_App(t *testing.T) {
+	dispatcher := application.NewDispatcher()
+	app := New(
+		t,
+		[]contracts.Provider{transport.NewTransportProvider()},
+		WithOverride(func() contracts.Dispatcher { return dispatcher }),
+	).Start()
+
+	if got := di.Get[contracts.Dispatcher](app); got != dispatcher {
+		t.Errorf("overridden dispatcher = %v, want %v", got, dispatcher)
+	}
+
+	_, err := grpcHealthV1.NewHealthClient(app.GrpcConn()).Check(context.Background(), &grpcHealthV1.HealthCheckRequest{})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("grpc call error = %v, want %v", err, codes.Unimplemented)
+	}
+
+	resp, err := http.Get(app.HttpURL() + "/unknown")
+	if err != nil {
+		t.Fatalf("http call error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("http call status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
@@ -14,6 +14,9 @@ import (
 	"syscall"
 )
 
+// redacted replaces secret values in outputs.
+const redacted = "******"
+
 type appCommand struct {
 	app contracts.Application
 	cfg contracts.Config
@@ -34,7 +37,15 @@ func NewAppCommands(app contracts.Application, cfg contracts.Config, log logger.
 			Flags: []cli.Flag{
 				&cli.StringFlag{
 					Name:  "out",
-					Usage: "Out variant (compose, helm)",
+					Usage: "Out variant (compose, configmap, helm, json, yaml, dotenv, schema)",
+				},
+				&cli.BoolFlag{
+					Name:  "diff",
+					Usage: "Print values which differ from defaults and unknown environment variables",
+				},
+				&cli.StringFlag{
+					Name:  "secret",
+					Usage: "Kubernetes secret name referenced by helm out, defaults to <app name>-secrets",
 				},
 			},
 			Action: cmd.appEnv,
@@ -49,21 +60,19 @@ func NewAppCommands(app contracts.Application, cfg contracts.Config, log logger.
 }
 
 func (c *appCommand) appEnv(ctx *cli.Context) error {
-	type configFlag struct {
-		Value    string
-		Default  string
-		Usage    string
-		Provider []string
-	}
-	configs := make(map[string]configFlag)
+	configs := make(map[string]envFlag)
 	c.cfg.Visit(
 		func(f contracts.ConfigFlag) {
 			if f.Name() != flag.DefaultConfigFlagname {
 				if _, ok := configs[f.Name()]; !ok {
-					configs[f.Name()] = configFlag{
+					configs[f.Name()] = envFlag{
+						Name:    f.Name(),
 						Value:   f.Value(),
 						Default: f.Default(),
 						Usage:   f.Usage(),
+						Type:    f.Type(),
+						Source:  f.Source(),
+						Secret:  f.Secret(),
 					}
 				}
 				cfg := configs[f.Name()]
@@ -77,13 +86,33 @@ func (c *appCommand) appEnv(ctx *cli.Context) error {
 		keys = append(keys, name)
 	}
 	sort.Strings(keys)
+	flags := make([]envFlag, len(keys))
+	for i, key := range keys {
+		flags[i] = configs[key]
+	}
+
+	if ctx.Bool("diff") {
+		writeEnvDiff(os.Stdout, flags, os.Environ())
+		return nil
+	}
 
 	switch ctx.String("out") {
+	case "json":
+		return writeEnvJSON(os.Stdout, flags)
+	case "yaml":
+		return writeEnvYAML(os.Stdout, flags)
+	case "dotenv":
+		return writeEnvExample(os.Stdout, flags)
+	case "schema":
+		return writeEnvSchema(os.Stdout, c.app.Name(), flags)
 	case "configmap":
 		fmt.Println("--------------------------------")
 		fmt.Println("data:")
 		for _, key := range keys {
 			cfg := configs[key]
+			if cfg.Secret {
+				continue
+			}
 			fmt.Printf("  %s: '%s'\n", key, cfg.Value)
 		}
 		fmt.Println("--------------------------------")
@@ -92,39 +121,92 @@ func (c *appCommand) appEnv(ctx *cli.Context) error {
 		fmt.Println("environment:")
 		for _, key := range keys {
 			cfg := configs[key]
+			if cfg.Secret {
+				// substituted from the shell environment or .env file by compose
+				fmt.Printf("  - %s=${%s}\n", key, key)
+				continue
+			}
 			fmt.Printf("  - %s=%s\n", key, cfg.Value)
 		}
 		fmt.Println("--------------------------------")
 	case "helm":
 		fmt.Println("--------------------------------")
+		secretName := ctx.String("secret")
+		if secretName == "" {
+			secretName = c.app.Name() + "-secrets"
+		}
+		var secrets []string
 		fmt.Println("env:")
 		for _, key := range keys {
 			cfg := configs[key]
 			fmt.Printf("  - name: %s\n", key)
+			if cfg.Secret {
+				secrets = append(secrets, key)
+				fmt.Println("    valueFrom:")
+				fmt.Println("      secretKeyRef:")
+				fmt.Printf("        name: %s\n", secretName)
+				fmt.Printf("        key: %s\n", key)
+				continue
+			}
 			fmt.Printf("    value: \"%s\"\n", cfg.Value)
 		}
+		if len(secrets) > 0 {
+			fmt.Println("--------------------------------")
+			fmt.Println("apiVersion: v1")
+			fmt.Println("kind: Secret")
+			fmt.Println("metadata:")
+			fmt.Printf("  name: %s\n", secretName)
+			fmt.Println("type: Opaque")
+			fmt.Println("stringData:")
+			for _, key := range secrets {
+				fmt.Printf("  %s: ''\n", key)
+			}
+		}
 		fmt.Println("--------------------------------")
 	default:
 		t := table.NewWriter()
 		t.SetOutputMirror(os.Stdout)
-		t.AppendHeader(table.Row{"Name", "Default", "Current", "Usage", "Provider"})
+		t.AppendHeader(table.Row{"Name", "Default", "Current", "Source", "Usage", "Provider"})
 		for _, key := range keys {
-			cfg := configs[key]
-			t.AppendRow(table.Row{key, cfg.Default, cfg.Value, cfg.Usage, strings.Join(cfg.Provider, ", ")})
+			cfg := configs[key].redacted()
+			t.AppendRow(table.Row{key, cfg.Default, cfg.Value, cfg.Source, cfg.Usage, strings.Join(cfg.Provider, ", ")})
 		}
 		t.Render()
 	}
 	return nil
 }
 
+// redact masks non-empty values, so it is still visible whether a secret is set.
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redacted
+}
+
 //goland:noinspection SpellCheckingInspection
-func (c *appCommand) appStart(*cli.Context) error {
-	done := make(chan os.Signal)
-	signal.Notify(done, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT) //nolint:govet,staticcheck
+func (c *appCommand) appStart(ctx *cli.Context) error {
+	done := make(chan os.Signal, 2)
+	signal.Notify(done, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
+	defer signal.Stop(done)
 
-	c.app.InitService()
-	c.app.StartService()
+	if err := c.app.InitServices(ctx.Context); err != nil {
+		return err
+	}
+	if err := c.app.StartServices(ctx.Context); err != nil {
+		return err
+	}
+
+	c.log.Infow("shutdown: signal received", "signal", (<-done).String())
+	go func() {
+		c.log.Warnw("shutdown: forced exit", "signal", (<-done).String())
+		os.Exit(1)
+	}()
+
+	if err := c.app.Shutdown(ctx.Context); err != nil {
+		return err
+	}
 
-	c.log.Infow("application is finished", "signal", (<-done).String())
+	c.log.Infow("application is finished")
 	return nil
 }
@@ -0,0 +1,238 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/namsral/flag"
+	"gopkg.in/yaml.v3"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// envFlag is a config value as printed by app:env.
+type envFlag struct {
+	Name     string   `json:"name" yaml:"name"`
+	Value    string   `json:"value" yaml:"value"`
+	Default  string   `json:"default" yaml:"default"`
+	Usage    string   `json:"usage" yaml:"usage"`
+	Type     string   `json:"type" yaml:"type"`
+	Source   string   `json:"source" yaml:"source"`
+	Secret   bool     `json:"secret" yaml:"secret"`
+	Provider []string `json:"provider" yaml:"provider"`
+}
+
+// redacted returns the flag with secret values masked.
+func (f envFlag) redacted() envFlag {
+	if f.Secret {
+		f.Default, f.Value = redact(f.Default), redact(f.Value)
+	}
+	return f
+}
+
+func writeEnvJSON(w io.Writer, flags []envFlag) error {
+	items := make([]envFlag, len(flags))
+	for i, f := range flags {
+		items[i] = f.redacted()
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(items)
+}
+
+func writeEnvYAML(w io.Writer, flags []envFlag) error {
+	items := make([]envFlag, len(flags))
+	for i, f := range flags {
+		items[i] = f.redacted()
+	}
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(items); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// writeEnvExample writes a .env.example file with default values, secrets are left empty.
+func writeEnvExample(w io.Writer, flags []envFlag) error {
+	for i, f := range flags {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		comment := f.Type
+		if f.Secret {
+			comment += ", secret"
+		}
+		if f.Usage != "" {
+			comment = f.Usage + " (" + comment + ")"
+		}
+		value := f.Default
+		if f.Secret {
+			value = ""
+		}
+		if _, err := fmt.Fprintf(w, "# %s\n%s=%s\n", comment, f.Name, dotEnvQuote(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dotEnvQuote quotes values with spaces, quotes or comment signs.
+func dotEnvQuote(value string) string {
+	if strings.ContainsAny(value, " \t\"'#\\") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// writeEnvSchema writes a JSON Schema of an object with all config values as properties.
+func writeEnvSchema(w io.Writer, appName string, flags []envFlag) error {
+	properties := make(map[string]interface{}, len(flags))
+	for _, f := range flags {
+		property := map[string]interface{}{
+			"type":        schemaType(f.Type),
+			"description": f.Usage,
+		}
+		if f.Secret {
+			property["writeOnly"] = true
+		} else if def, ok := schemaDefault(f.Type, f.Default); ok {
+			property["default"] = def
+		}
+		properties[f.Name] = property
+	}
+	schema := map[string]interface{}{
+		"$schema":    "https://json-schema.org/draft/2020-12/schema",
+		"title":      appName + " config",
+		"type":       "object",
+		"properties": properties,
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(schema)
+}
+
+func schemaType(typ string) string {
+	switch typ {
+	case "bool":
+		return "boolean"
+	case "int", "uint":
+		return "integer"
+	case "float":
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+// schemaDefault converts the default value to the schema type, empty defaults are omitted.
+func schemaDefault(typ, value string) (interface{}, bool) {
+	if value == "" {
+		return nil, false
+	}
+	switch schemaType(typ) {
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		return b, err == nil
+	case "integer":
+		n, err := strconv.ParseInt(value, 0, 64)
+		return n, err == nil
+	case "number":
+		n, err := strconv.ParseFloat(value, 64)
+		return n, err == nil
+	default:
+		return value, true
+	}
+}
+
+// writeEnvDiff writes values which differ from defaults and environment variables no provider declared,
+// the environment is given as KEY=value items.
+func writeEnvDiff(w io.Writer, flags []envFlag, environ []string) {
+	_, _ = fmt.Fprintln(w, "Values differing from defaults:")
+	changed := table.NewWriter()
+	changed.SetOutputMirror(w)
+	changed.AppendHeader(table.Row{"Name", "Default", "Current", "Source"})
+	for _, f := range flags {
+		if f.Value != f.Default {
+			f = f.redacted()
+			changed.AppendRow(table.Row{f.Name, f.Default, f.Value, f.Source})
+		}
+	}
+	changed.Render()
+
+	unknown := unknownEnv(flags, environ)
+	if len(unknown) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "\nUnknown variables, likely typos:")
+	undeclared := table.NewWriter()
+	undeclared.SetOutputMirror(w)
+	undeclared.AppendHeader(table.Row{"Name", "Did you mean"})
+	for _, name := range unknown {
+		undeclared.AppendRow(table.Row{name, closestName(name, flags)})
+	}
+	undeclared.Render()
+}
+
+// unknownEnv returns environment variables sharing a prefix with declared values, like APP_ or DB_,
+// which no provider declared. Secret file variables and the config files variable are known.
+func unknownEnv(flags []envFlag, environ []string) []string {
+	known := make(map[string]bool, len(flags))
+	prefixes := make(map[string]bool)
+	for _, f := range flags {
+		name := envName(f.Name)
+		known[name] = true
+		if f.Secret {
+			known[name+"_FILE"] = true
+		}
+		if prefix, _, ok := strings.Cut(name, "_"); ok {
+			prefixes[prefix+"_"] = true
+		}
+	}
+	known[envName(flag.DefaultConfigFlagname)] = true
+
+	var unknown []string
+	for _, item := range environ {
+		name, _, _ := strings.Cut(item, "=")
+		prefix, _, ok := strings.Cut(name, "_")
+		if ok && prefixes[prefix+"_"] && !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// closestName returns the declared name with the smallest edit distance, distant names are not suggested.
+func closestName(name string, flags []envFlag) string {
+	best, bestDistance := "", len(name)/3+1
+	for _, f := range flags {
+		if d := editDistance(name, envName(f.Name)); d < bestDistance {
+			best, bestDistance = f.Name, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
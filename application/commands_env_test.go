@@ -0,0 +1,42 @@
+package application
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_unknownEnv(t *testing.T) {
+	flags := []envFlag{
+		{Name: "APP_NAME"},
+		{Name: "DB_HOST"},
+		{Name: "DB_PASS", Secret: true},
+	}
+	environ := []string{
+		"APP_NAME=app",
+		"APP_NAEM=app",
+		"DB_PASS_FILE=/run/secrets/db",
+		"DB_HSOT=mysql",
+		"CONFIG=config.yaml",
+		"HOME=/root",
+		"PATH_EXTRA=/bin",
+	}
+
+	tests := []struct {
+		name    string
+		closest string
+	}{
+		{name: "APP_NAEM", closest: "APP_NAME"},
+		{name: "DB_HSOT", closest: "DB_HOST"},
+	}
+	unknown := unknownEnv(flags, environ)
+	if want := []string{"APP_NAEM", "DB_HSOT"}; !reflect.DeepEqual(unknown, want) {
+		t.Fatalf("unknownEnv() = %v, want %v", unknown, want)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := closestName(tt.name, flags); got != tt.closest {
+				t.Errorf("closestName() = %v, want %v", got, tt.closest)
+			}
+		})
+	}
+}
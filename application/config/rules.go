@@ -0,0 +1,160 @@
+package config
+
+import (
+	"fmt"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Required fails when no source sets the value and it falls back to the default.
+//
+//goland:noinspection GoUnusedExportedFunction
+func Required() contracts.ConfigRule {
+	return func(value string, explicit bool) error {
+		if !explicit {
+			return fmt.Errorf("value is required")
+		}
+		return nil
+	}
+}
+
+// Min fails when the numeric value is less than the limit, empty values are skipped.
+//
+//goland:noinspection GoUnusedExportedFunction
+func Min(limit float64) contracts.ConfigRule {
+	return func(value string, _ bool) error {
+		if value == "" {
+			return nil
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("value %q is not a number", value)
+		}
+		if n < limit {
+			return fmt.Errorf("value %s must be at least %v", value, limit)
+		}
+		return nil
+	}
+}
+
+// Max fails when the numeric value is greater than the limit, empty values are skipped.
+//
+//goland:noinspection GoUnusedExportedFunction
+func Max(limit float64) contracts.ConfigRule {
+	return func(value string, _ bool) error {
+		if value == "" {
+			return nil
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("value %q is not a number", value)
+		}
+		if n > limit {
+			return fmt.Errorf("value %s must be at most %v", value, limit)
+		}
+		return nil
+	}
+}
+
+// OneOf fails when the value is not one of the allowed values, empty values are skipped.
+//
+//goland:noinspection GoUnusedExportedFunction
+func OneOf(values ...string) contracts.ConfigRule {
+	return func(value string, _ bool) error {
+		if value == "" {
+			return nil
+		}
+		for _, allowed := range values {
+			if value == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("value %q must be one of: %s", value, strings.Join(values, ", "))
+	}
+}
+
+// Pattern fails when the value does not match the regular expression, empty values are skipped.
+//
+//goland:noinspection GoUnusedExportedFunction
+func Pattern(pattern string) contracts.ConfigRule {
+	re := regexp.MustCompile(pattern)
+	return func(value string, _ bool) error {
+		if value == "" || re.MatchString(value) {
+			return nil
+		}
+		return fmt.Errorf("value %q must match %s", value, pattern)
+	}
+}
+
+// HostPort fails when the value is not a host:port address, empty values are skipped.
+//
+//goland:noinspection GoUnusedExportedFunction
+func HostPort() contracts.ConfigRule {
+	return func(value string, _ bool) error {
+		if value == "" {
+			return nil
+		}
+		_, port, err := net.SplitHostPort(value)
+		if err != nil {
+			return fmt.Errorf("value %q must be a host:port address", value)
+		}
+		if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+			return fmt.Errorf("value %q has invalid port", value)
+		}
+		return nil
+	}
+}
+
+// FromTags builds rules of the struct field tags: required:"true", min:"1", max:"10",
+// enum:"a,b", pattern:"^[a-z]+$" and format:"hostport".
+func FromTags(tag func(key string) (string, bool)) ([]contracts.ConfigRule, error) {
+	var rules []contracts.ConfigRule
+	if value, ok := tag("required"); ok {
+		required, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid required tag %q", value)
+		}
+		if required {
+			rules = append(rules, Required())
+		}
+	}
+	if value, ok := tag("min"); ok {
+		limit, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min tag %q", value)
+		}
+		rules = append(rules, Min(limit))
+	}
+	if value, ok := tag("max"); ok {
+		limit, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max tag %q", value)
+		}
+		rules = append(rules, Max(limit))
+	}
+	if value, ok := tag("enum"); ok {
+		values := strings.Split(value, ",")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+		rules = append(rules, OneOf(values...))
+	}
+	if value, ok := tag("pattern"); ok {
+		if _, err := regexp.Compile(value); err != nil {
+			return nil, fmt.Errorf("invalid pattern tag %q: %s", value, err)
+		}
+		rules = append(rules, Pattern(value))
+	}
+	if value, ok := tag("format"); ok {
+		switch value {
+		case "hostport":
+			rules = append(rules, HostPort())
+		default:
+			return nil, fmt.Errorf("unknown format tag %q", value)
+		}
+	}
+	return rules, nil
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+)
+
+// SecretVar binds a secret value, sets without secrets support bind it as a plain string.
+//
+//goland:noinspection GoUnusedExportedFunction
+func SecretVar(c contracts.ConfigSet, p *string, name string, value string, usage string) {
+	if ext, ok := c.(contracts.ConfigSetExt); ok {
+		ext.SecretVar(p, name, value, usage)
+		return
+	}
+	c.StringVar(p, name, value, usage)
+}
+
+// Rules adds validation rules of the named value, sets without validation support skip them.
+//
+//goland:noinspection GoUnusedExportedFunction
+func Rules(c contracts.ConfigSet, name string, rules ...contracts.ConfigRule) {
+	if ext, ok := c.(contracts.ConfigSetExt); ok {
+		ext.Rules(name, rules...)
+	}
+}
+
+// Struct binds the tagged fields of the target, it panics when the set does not support struct binding.
+//
+//goland:noinspection GoUnusedExportedFunction
+func Struct(c contracts.ConfigSet, target interface{}) {
+	ext, ok := c.(contracts.ConfigSetExt)
+	if !ok {
+		panic(fmt.Errorf("config set %T does not support struct binding", c))
+	}
+	ext.Struct(target)
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/namsral/flag"
+	"testing"
+)
+
+// plainSet is a ConfigSet without the extensions of the application config sets.
+type plainSet struct {
+	*flag.FlagSet
+}
+
+var _ contracts.ConfigSet = plainSet{}
+
+func Test_SetHelpersOnPlainSet(t *testing.T) {
+	set := plainSet{flag.NewFlagSet("test", flag.ContinueOnError)}
+
+	var pass string
+	SecretVar(set, &pass, "TEST_PASS", "secret", "password")
+	Rules(set, "TEST_PASS", Required())
+
+	if err := set.Parse([]string{"-TEST_PASS=changed"}); err != nil {
+		t.Fatal(err)
+	}
+	if pass != "changed" {
+		t.Errorf("SecretVar() value = %q, want %q", pass, "changed")
+	}
+}
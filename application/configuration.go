@@ -3,18 +3,40 @@ package application
 import (
 	"fmt"
 	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/errors"
 	"github.com/namsral/flag"
+	"os"
+	"sort"
+	"strings"
+	"sync"
 )
 
 type defaultConfig struct {
-	configs map[string]*flag.FlagSet
+	mu          sync.RWMutex
+	configs     map[string]*flag.FlagSet
+	sources     map[string]string // flag name to the source its value came from
+	raw         map[string]string // flag name to the value as read from its source
+	code        map[string]string // values set from code, they are kept on reload
+	arguments   []string          // parsed arguments, they are parsed again on reload
+	args        []string          // arguments left after flags
+	files       []string          // config and secret files values are read from
+	rules       map[string][]contracts.ConfigRule
+	secrets     map[string]bool
+	subscribers map[string][]func(value string)
+	validate    func() error // cross-validation hooks of the providers, they are run on reload
 }
 
 var _ contracts.Config = (*defaultConfig)(nil)
 
 func newConfig() contracts.Config {
 	return &defaultConfig{
-		configs: make(map[string]*flag.FlagSet),
+		configs:     make(map[string]*flag.FlagSet),
+		sources:     make(map[string]string),
+		raw:         make(map[string]string),
+		code:        make(map[string]string),
+		rules:       make(map[string][]contracts.ConfigRule),
+		secrets:     make(map[string]bool),
+		subscribers: make(map[string][]func(value string)),
 	}
 }
 
@@ -23,27 +45,201 @@ func (c *defaultConfig) New(provider string) contracts.ConfigSet {
 		panic(fmt.Errorf(`flag set with name "%s" already registered`, provider))
 	}
 	c.configs[provider] = flag.NewFlagSet(provider, flag.PanicOnError)
-	c.configs[provider].String(flag.DefaultConfigFlagname, "", "path to config file")
-	return c.configs[provider]
+	c.configs[provider].String(flag.DefaultConfigFlagname, "", "comma-separated paths to YAML, JSON or .env config files")
+	return &configSet{FlagSet: c.configs[provider], config: c}
 }
 
+// Parse applies values layer by layer: defaults, config files, environment, secret files and command line flags,
+// config files are listed in the CONFIG environment variable and -config flags, each file is followed
+// by its APP_ENV profile file like config.production.yaml when it exists,
+// secret files are named in NAME_FILE environment variables.
 func (c *defaultConfig) Parse(arguments []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	layers, rest, err := c.layers(arguments)
+	if err != nil {
+		panic(err)
+	}
+	c.arguments, c.args = arguments, rest
+	c.files = layerFiles(layers)
+
+	for _, layer := range layers {
+		for name, value := range layer.values {
+			if name == flag.DefaultConfigFlagname {
+				continue
+			}
+			if err = c.set(name, value, layer.source); err != nil {
+				panic(err)
+			}
+		}
+	}
+}
+
+// layers reads config files, the environment, secret files and command line flags in order of precedence.
+func (c *defaultConfig) layers(arguments []string) ([]configLayer, []string, error) {
+	args, rest, err := parseConfigArgs(arguments, c.lookup)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse flags error: %s", err)
+	}
+	env := envLayer(flagNames(c.configs))
+
+	files := configFiles(env.values[flag.DefaultConfigFlagname], args.values[flag.DefaultConfigFlagname])
+	base := make([]configLayer, len(files))
+	for i, file := range files {
+		if base[i], err = loadConfigFile(file); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// the profile is picked from the layers, so APP_ENV may be set in any of them
+	profile := c.defValue(appEnvFlagName)
+	for _, layer := range append(base, env, args) {
+		if value, ok := layer.values[appEnvFlagName]; ok {
+			profile = value
+		}
+	}
+
+	var layers []configLayer
+	for i, file := range files {
+		layers = append(layers, base[i])
+		if profile == "" {
+			continue
+		}
+		profileFile := profileConfigFile(file, profile)
+		if _, err = os.Stat(profileFile); os.IsNotExist(err) {
+			continue
+		}
+		layer, err := loadConfigFile(profileFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		layers = append(layers, layer)
+	}
+	layers = append(layers, env)
+
+	secrets, err := secretFileLayers(c.secretNames(), env)
+	if err != nil {
+		return nil, nil, err
+	}
+	layers = append(layers, secrets...)
+	layers = append(layers, args)
+	return layers, rest, nil
+}
+
+// Args returns the arguments left after the flags.
+func (c *defaultConfig) Args() []string {
+	return c.args
+}
+
+// Set sets the value of every flag with the name, unknown names are ignored.
+func (c *defaultConfig) Set(name, value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.set(name, value, configSourceCode); err != nil {
+		return err
+	}
+	c.code[name] = value
+	return nil
+}
+
+func (c *defaultConfig) set(name, value, source string) error {
 	for group, set := range c.configs {
-		if err := set.Parse(arguments); err != nil {
-			panic(fmt.Errorf(`parse "%s" error: %s`, group, err))
+		if set.Lookup(name) == nil {
+			continue
 		}
+		if err := set.Set(name, value); err != nil {
+			return fmt.Errorf(`set "%s" from %s in "%s" error: %s`, name, source, group, err)
+		}
+		c.sources[name] = source
+		c.raw[name] = value
 	}
+	return nil
+}
+
+// Validate checks every value against its rules and reports all violations at once.
+func (c *defaultConfig) Validate() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.rules))
+	for name := range c.rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	errs := errors.NewMultiError()
+	for _, name := range names {
+		errs.Append(c.check(name)...)
+	}
+	return errs.ErrorOrNil()
+}
+
+// check returns violations of the named value rules.
+func (c *defaultConfig) check(name string) []error {
+	value, ok := c.value(name)
+	if !ok {
+		return nil
+	}
+	_, explicit := c.sources[name]
+
+	var errs []error
+	for _, rule := range c.rules[name] {
+		if err := rule(value, explicit); err != nil {
+			errs = append(errs, errors.NewFieldValidationError(name, "%s", err))
+		}
+	}
+	return errs
+}
+
+func (c *defaultConfig) value(name string) (string, bool) {
+	for _, set := range c.configs {
+		if f := set.Lookup(name); f != nil {
+			return f.Value.String(), true
+		}
+	}
+	return "", false
+}
+
+func (c *defaultConfig) secretNames() []string {
+	names := make([]string, 0, len(c.secrets))
+	for name := range c.secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c *defaultConfig) lookup(name string) (isBool bool, ok bool) {
+	for _, set := range c.configs {
+		if f := set.Lookup(name); f != nil {
+			boolFlag, isBoolFlag := f.Value.(interface{ IsBoolFlag() bool })
+			return isBoolFlag && boolFlag.IsBoolFlag(), true
+		}
+	}
+	return false, false
 }
 
 func (c *defaultConfig) Visit(fn func(f contracts.ConfigFlag)) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	for group, set := range c.configs {
 		set.VisitAll(func(f *flag.Flag) {
+			source, ok := c.sources[f.Name]
+			if !ok {
+				source = configSourceDefault
+			}
 			fn(&defaultConfigFlag{
 				name:     f.Name,
 				usage:    f.Usage,
 				value:    f.Value.String(),
 				defValue: f.DefValue,
 				provider: group,
+				source:   source,
+				secret:   c.secrets[f.Name],
+				typ:      configValueType(f.Value),
 			})
 		})
 	}
@@ -55,9 +251,12 @@ type defaultConfigFlag struct {
 	value    string
 	defValue string
 	provider string
+	source   string
+	secret   bool
+	typ      string
 }
 
-var _ contracts.ConfigSet = (*flag.FlagSet)(nil)
+var _ contracts.ConfigSetExt = (*configSet)(nil)
 var _ contracts.ConfigFlag = (*defaultConfigFlag)(nil)
 
 func (f *defaultConfigFlag) Name() string {
@@ -79,3 +278,63 @@ func (f *defaultConfigFlag) Default() string {
 func (f *defaultConfigFlag) Provider() string {
 	return f.provider
 }
+
+func (f *defaultConfigFlag) Source() string {
+	return f.source
+}
+
+func (f *defaultConfigFlag) Secret() bool {
+	return f.secret
+}
+
+func (f *defaultConfigFlag) Type() string {
+	return f.typ
+}
+
+// configValidationErr reports all config violations found before the providers boot.
+type configValidationErr struct {
+	errs *errors.MultiErr
+}
+
+func (e *configValidationErr) Error() string {
+	var report strings.Builder
+	report.WriteString("config validation failed:")
+	for _, err := range e.errs.AllErrors() {
+		report.WriteString("\n  - ")
+		if fieldErr, ok := errors.AsErrOk[*errors.FieldValidationErr](err); ok {
+			report.WriteString(fieldErr.Field() + ": " + fieldErr.Reason())
+		} else {
+			report.WriteString(err.Error())
+		}
+	}
+	return report.String()
+}
+
+func (e *configValidationErr) AllErrors() []error {
+	return e.errs.AllErrors()
+}
+
+// validateConfig checks the config rules and the providers cross-validation hooks,
+// the hooks are kept to check reloaded values.
+func validateConfig(cfg contracts.Config, providers []contracts.Provider, env string) error {
+	validate := func() error {
+		errs := errors.NewMultiError()
+		for _, provider := range providers {
+			if validator, ok := provider.(contracts.ConfigValidator); ok {
+				errs.Append(validator.ValidateConfig(env))
+			}
+		}
+		return errs.ErrorOrNil()
+	}
+	if c, ok := cfg.(*defaultConfig); ok {
+		c.mu.Lock()
+		c.validate = validate
+		c.mu.Unlock()
+	}
+
+	errs := errors.NewMultiError().Append(cfg.Validate()).Append(validate())
+	if !errs.HasErrors() {
+		return nil
+	}
+	return &configValidationErr{errs: errs}
+}
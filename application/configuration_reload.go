@@ -0,0 +1,181 @@
+package application
+
+import (
+	"github.com/N-Vokhmyanin/go-framework/errors"
+	"github.com/namsral/flag"
+	"sort"
+	"strings"
+)
+
+// configValue is a value as read from its source.
+type configValue struct {
+	raw    string
+	source string
+}
+
+// configChange is a changed value with the subscribers to notify.
+type configChange struct {
+	value       string
+	subscribers []func(value string)
+}
+
+// Subscribe calls fn with the new value whenever the named value changes on reload.
+func (c *defaultConfig) Subscribe(name string, fn func(value string)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.subscribers[name] = append(c.subscribers[name], fn)
+}
+
+// Reload reads config files, the environment, secret files and the parsed flags again,
+// changed values with subscribers are applied and passed to them, values set from code are kept.
+// Other values are read once, their changes are not applied until the restart.
+// When any changed value is invalid, nothing is applied and all violations are returned.
+func (c *defaultConfig) Reload() error {
+	_, err := c.reloadAndNotify()
+	return err
+}
+
+// reloadAndNotify reloads the config, notifies subscribers and returns names of changed values
+// which require a restart.
+func (c *defaultConfig) reloadAndNotify() ([]string, error) {
+	changes, restart, err := c.reload()
+	if err != nil {
+		return nil, err
+	}
+	// subscribers are called without the lock, so they may read the config
+	for _, change := range changes {
+		for _, fn := range change.subscribers {
+			fn(change.value)
+		}
+	}
+	return restart, nil
+}
+
+func (c *defaultConfig) reload() ([]configChange, []string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	layers, _, err := c.layers(c.arguments)
+	if err != nil {
+		return nil, nil, err
+	}
+	layers = append(layers, configLayer{source: configSourceCode, values: c.code})
+
+	next := make(map[string]configValue)
+	for _, layer := range layers {
+		for name, value := range layer.values {
+			next[name] = configValue{raw: value, source: layer.source}
+		}
+	}
+
+	previous := make(map[string]configValue)
+	values := make(map[string]string)
+	var restart []string
+	errs := errors.NewMultiError()
+	for _, name := range flagNames(c.configs) {
+		if name == flag.DefaultConfigFlagname {
+			continue
+		}
+		current := c.current(name)
+		value, ok := next[name]
+		if !ok {
+			value = configValue{raw: c.defValue(name), source: configSourceDefault}
+		}
+		if value == current {
+			continue
+		}
+		// bound variables of values without subscribers are read without the lock, so they are not changed
+		if len(c.subscribers[name]) == 0 {
+			restart = append(restart, name)
+			continue
+		}
+
+		previous[name] = current
+		values[name], _ = c.value(name)
+		if err = c.apply(name, value); err != nil {
+			errs.Append(errors.NewFieldValidationError(name, "%s", err))
+		}
+	}
+	for name := range previous {
+		errs.Append(c.check(name)...)
+	}
+	if len(previous) > 0 && c.validate != nil {
+		errs.Append(c.validate())
+	}
+
+	if errs.HasErrors() {
+		for name, value := range previous {
+			_ = c.apply(name, value)
+		}
+		return nil, nil, &configValidationErr{errs: errs}
+	}
+	c.files = layerFiles(layers)
+
+	names := make([]string, 0, len(previous))
+	for name := range previous {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var changes []configChange
+	for _, name := range names {
+		value, _ := c.value(name)
+		if value == values[name] {
+			continue
+		}
+		changes = append(changes, configChange{
+			value:       value,
+			subscribers: append([]func(value string){}, c.subscribers[name]...),
+		})
+	}
+	return changes, restart, nil
+}
+
+// current returns the value of the name as read from its source.
+func (c *defaultConfig) current(name string) configValue {
+	if source, ok := c.sources[name]; ok {
+		return configValue{raw: c.raw[name], source: source}
+	}
+	return configValue{raw: c.defValue(name), source: configSourceDefault}
+}
+
+// apply sets the value, default values reset the source.
+func (c *defaultConfig) apply(name string, value configValue) error {
+	if err := c.set(name, value.raw, value.source); err != nil {
+		return err
+	}
+	if value.source == configSourceDefault {
+		delete(c.sources, name)
+		delete(c.raw, name)
+	}
+	return nil
+}
+
+func (c *defaultConfig) defValue(name string) string {
+	for _, set := range c.configs {
+		if f := set.Lookup(name); f != nil {
+			return f.DefValue
+		}
+	}
+	return ""
+}
+
+// watchedFiles returns config and secret files the values are read from.
+func (c *defaultConfig) watchedFiles() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]string(nil), c.files...)
+}
+
+// layerFiles returns paths of the file layers.
+func layerFiles(layers []configLayer) []string {
+	var files []string
+	for _, layer := range layers {
+		if path, ok := strings.CutPrefix(layer.source, configSourceFile+":"); ok {
+			files = append(files, path)
+		}
+	}
+	return files
+}
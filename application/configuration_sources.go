@@ -0,0 +1,237 @@
+package application
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/namsral/flag"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// configuration sources, later ones take precedence
+const (
+	configSourceDefault = "default"
+	configSourceFile    = "file"
+	configSourceEnv     = "env"
+	configSourceFlag    = "flag"
+	configSourceCode    = "code"
+)
+
+// configLayer is a set of values read from one source.
+type configLayer struct {
+	source string
+	values map[string]string
+}
+
+// loadConfigFile reads YAML, JSON or dotenv file, the format is detected by the file extension
+// and defaults to dotenv, nested keys are joined with underscores and upper-cased.
+func loadConfigFile(path string) (configLayer, error) {
+	layer := configLayer{source: configSourceFile + ":" + path, values: map[string]string{}}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return layer, fmt.Errorf(`read config file "%s": %w`, path, err)
+	}
+
+	var tree map[string]interface{}
+	switch ext := strings.ToLower(filepath.Ext(path)); {
+	case ext == ".yaml" || ext == ".yml":
+		err = yaml.Unmarshal(data, &tree)
+	case ext == ".json":
+		err = json.Unmarshal(data, &tree)
+	default:
+		layer.values, err = parseDotEnv(data)
+		if err != nil {
+			return layer, fmt.Errorf(`parse config file "%s": %w`, path, err)
+		}
+		return layer, nil
+	}
+	if err != nil {
+		return layer, fmt.Errorf(`parse config file "%s": %w`, path, err)
+	}
+
+	flattenConfig("", tree, layer.values)
+	return layer, nil
+}
+
+func flattenConfig(prefix string, value interface{}, out map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			flattenConfig(configKey(prefix, key), item, out)
+		}
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		out[prefix] = strings.Join(items, ",")
+	case nil:
+		out[prefix] = ""
+	case float64:
+		out[prefix] = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		out[prefix] = fmt.Sprint(v)
+	}
+}
+
+func configKey(prefix, key string) string {
+	key = strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+	if prefix == "" {
+		return key
+	}
+	return prefix + "_" + key
+}
+
+// parseDotEnv parses KEY=value lines, blank lines and lines starting with # are skipped.
+// Lines of the namsral flag format are accepted too: KEY value and bare KEY, which sets a boolean to true.
+func parseDotEnv(data []byte) (map[string]string, error) {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimPrefix(text, "export ")
+
+		i := strings.IndexAny(text, "= \t")
+		if i < 0 {
+			values[text] = "true"
+			continue
+		}
+		key := text[:i]
+		value := strings.TrimSpace(text[i:])
+		value = strings.TrimSpace(strings.TrimPrefix(value, "="))
+		if unquoted, err := strconv.Unquote(value); err == nil && len(value) > 1 && value[0] == '"' {
+			value = unquoted
+		} else if len(value) > 1 && value[0] == '\'' && value[len(value)-1] == '\'' {
+			value = value[1 : len(value)-1]
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
+
+// envLayer reads environment variables named after the flags.
+func envLayer(names []string) configLayer {
+	layer := configLayer{source: configSourceEnv, values: map[string]string{}}
+	for _, name := range names {
+		if value, ok := os.LookupEnv(envName(name)); ok {
+			layer.values[name] = value
+		}
+	}
+	return layer
+}
+
+// secretFileLayers reads secrets from files named in NAME_FILE environment variables
+// following the Docker and Kubernetes secrets convention, trailing line breaks are trimmed.
+func secretFileLayers(names []string, env configLayer) ([]configLayer, error) {
+	var layers []configLayer
+	for _, name := range names {
+		path, ok := os.LookupEnv(envName(name) + "_FILE")
+		if !ok {
+			continue
+		}
+		if _, ok = env.values[name]; ok {
+			return nil, fmt.Errorf("both %s and %s_FILE environment variables are set", envName(name), envName(name))
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf(`read secret file "%s": %w`, path, err)
+		}
+		layers = append(layers, configLayer{
+			source: configSourceFile + ":" + path,
+			values: map[string]string{name: strings.TrimRight(string(data), "\r\n")},
+		})
+	}
+	return layers, nil
+}
+
+func envName(name string) string {
+	return strings.ReplaceAll(strings.ToUpper(name), "-", "_")
+}
+
+// parseConfigArgs reads leading -NAME=value, -NAME value and boolean -NAME arguments,
+// parsing stops at the first non-flag argument or "--", the rest of the arguments is returned.
+func parseConfigArgs(
+	arguments []string,
+	lookup func(name string) (isBool bool, ok bool),
+) (layer configLayer, rest []string, err error) {
+	layer = configLayer{source: configSourceFlag, values: map[string]string{}}
+	i := 0
+	for ; i < len(arguments); i++ {
+		arg := arguments[i]
+		if arg == "--" {
+			i++
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			break
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		isBool, ok := lookup(name)
+		if !ok {
+			return layer, nil, fmt.Errorf("flag provided but not defined: -%s", name)
+		}
+		if !hasValue {
+			switch {
+			case isBool:
+				value = "true"
+			case i+1 < len(arguments):
+				i++
+				value = arguments[i]
+			default:
+				return layer, nil, fmt.Errorf("flag needs an argument: -%s", name)
+			}
+		}
+		if previous, ok := layer.values[name]; ok && name == flag.DefaultConfigFlagname {
+			value = previous + "," + value
+		}
+		layer.values[name] = value
+	}
+	return layer, arguments[i:], nil
+}
+
+// profileConfigFile returns the profile variant of the config file: config.yaml is followed
+// by config.production.yaml and .env by .env.production.
+func profileConfigFile(path, profile string) string {
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(path, ext)
+	if stem == "" || strings.HasSuffix(stem, string(filepath.Separator)) {
+		return path + "." + profile
+	}
+	return stem + "." + profile + ext
+}
+
+// configFiles splits comma-separated config file lists.
+func configFiles(lists ...string) []string {
+	var files []string
+	for _, list := range lists {
+		files = append(files, splitConfigList(list)...)
+	}
+	return files
+}
+
+// flagNames returns names of flags defined in the sets.
+func flagNames(sets map[string]*flag.FlagSet) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, set := range sets {
+		set.VisitAll(func(f *flag.Flag) {
+			if !seen[f.Name] {
+				seen[f.Name] = true
+				names = append(names, f.Name)
+			}
+		})
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,306 @@
+package application
+
+import (
+	"encoding"
+	"fmt"
+	"github.com/N-Vokhmyanin/go-framework/application/config"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/namsral/flag"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	urlType             = reflect.TypeOf(url.URL{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+type configSet struct {
+	*flag.FlagSet
+	config *defaultConfig
+}
+
+// SecretVar binds a secret value.
+func (s *configSet) SecretVar(p *string, name string, value string, usage string) {
+	s.StringVar(p, name, value, usage)
+	s.config.secrets[name] = true
+}
+
+// Rules adds validation rules of the named value.
+func (s *configSet) Rules(name string, rules ...contracts.ConfigRule) {
+	s.config.rules[name] = append(s.config.rules[name], rules...)
+}
+
+// Struct binds exported fields tagged with `env:"NAME" default:"value" usage:"text"`,
+// nested structs are bound with their `prefix:"PREFIX_"` tag prepended to the names,
+// validation rules are read from required, min, max, enum, pattern and format tags,
+// fields tagged with `secret:"true"` are bound as secrets.
+func (s *configSet) Struct(target interface{}) {
+	value := reflect.ValueOf(target)
+	if !value.IsValid() || value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		panic("the config target must be a reference to a struct")
+	}
+	s.bindStruct(value.Elem(), "")
+}
+
+func (s *configSet) bindStruct(value reflect.Value, prefix string) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, ok := field.Tag.Lookup("env")
+		if !ok {
+			if isConfigStruct(field.Type) {
+				s.bindStruct(value.Field(i), prefix+field.Tag.Get("prefix"))
+			}
+			continue
+		}
+
+		configValue := &structConfigValue{value: value.Field(i)}
+		if def, ok := field.Tag.Lookup("default"); ok {
+			if err := configValue.Set(def); err != nil {
+				panic(fmt.Errorf(`invalid default value of "%s": %s`, prefix+name, err))
+			}
+		}
+		s.Var(configValue, prefix+name, field.Tag.Get("usage"))
+
+		rules, err := config.FromTags(field.Tag.Lookup)
+		if err != nil {
+			panic(fmt.Errorf(`invalid rules of "%s": %s`, prefix+name, err))
+		}
+		s.Rules(prefix+name, rules...)
+
+		if secret, _ := strconv.ParseBool(field.Tag.Get("secret")); secret {
+			s.config.secrets[prefix+name] = true
+		}
+	}
+}
+
+// isConfigStruct reports whether the type is bound field by field rather than parsed from a string.
+func isConfigStruct(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct &&
+		typ != urlType &&
+		!reflect.PtrTo(typ).Implements(textUnmarshalerType)
+}
+
+// structConfigValue is a flag value backed by a struct field.
+type structConfigValue struct {
+	value reflect.Value
+}
+
+func (v *structConfigValue) String() string {
+	if !v.value.IsValid() {
+		return ""
+	}
+	return formatConfigValue(v.value)
+}
+
+func (v *structConfigValue) Set(s string) error {
+	return parseConfigValue(v.value, s)
+}
+
+func (v *structConfigValue) Get() interface{} {
+	return v.value.Interface()
+}
+
+func (v *structConfigValue) IsBoolFlag() bool {
+	return v.value.Kind() == reflect.Bool
+}
+
+func parseConfigValue(value reflect.Value, s string) error {
+	if reflect.PtrTo(value.Type()).Implements(textUnmarshalerType) {
+		return value.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch value.Type() {
+	case durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(d))
+		return nil
+	case urlType:
+		u, err := url.Parse(s)
+		if err != nil {
+			return err
+		}
+		value.Set(reflect.ValueOf(*u))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr:
+		if s == "" {
+			value.Set(reflect.Zero(value.Type()))
+			return nil
+		}
+		item := reflect.New(value.Type().Elem())
+		if err := parseConfigValue(item.Elem(), s); err != nil {
+			return err
+		}
+		value.Set(item)
+	case reflect.String:
+		value.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 0, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 0, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(n)
+	case reflect.Slice:
+		items := splitConfigList(s)
+		slice := reflect.MakeSlice(value.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := parseConfigValue(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		value.Set(slice)
+	case reflect.Map:
+		items := splitConfigList(s)
+		m := reflect.MakeMapWithSize(value.Type(), len(items))
+		for _, item := range items {
+			k, v, ok := strings.Cut(item, "=")
+			if !ok {
+				return fmt.Errorf(`map item "%s" must be formatted as key=value`, item)
+			}
+			key := reflect.New(value.Type().Key()).Elem()
+			if err := parseConfigValue(key, strings.TrimSpace(k)); err != nil {
+				return err
+			}
+			elem := reflect.New(value.Type().Elem()).Elem()
+			if err := parseConfigValue(elem, strings.TrimSpace(v)); err != nil {
+				return err
+			}
+			m.SetMapIndex(key, elem)
+		}
+		value.Set(m)
+	default:
+		return fmt.Errorf("unsupported config type %s", value.Type())
+	}
+	return nil
+}
+
+// configValueType returns the kind of the flag value.
+func configValueType(value flag.Value) string {
+	getter, ok := value.(flag.Getter)
+	if !ok || getter.Get() == nil {
+		return "string"
+	}
+	typ := reflect.TypeOf(getter.Get())
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch typ {
+	case durationType:
+		return "duration"
+	case urlType:
+		return "url"
+	}
+	if reflect.PtrTo(typ).Implements(textUnmarshalerType) {
+		return "string"
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.Slice:
+		return "list"
+	case reflect.Map:
+		return "map"
+	default:
+		return "string"
+	}
+}
+
+// splitConfigList splits comma-separated items, an empty string is an empty list.
+func splitConfigList(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	items := strings.Split(s, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items
+}
+
+func formatConfigValue(value reflect.Value) string {
+	if value.Type().Implements(textMarshalerType) && (value.Kind() != reflect.Ptr || !value.IsNil()) {
+		text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+		if err == nil {
+			return string(text)
+		}
+	}
+	if value.CanAddr() && reflect.PtrTo(value.Type()).Implements(textMarshalerType) {
+		text, err := value.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		if err == nil {
+			return string(text)
+		}
+	}
+
+	switch value.Type() {
+	case durationType:
+		return time.Duration(value.Int()).String()
+	case urlType:
+		u := value.Interface().(url.URL)
+		return u.String()
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return ""
+		}
+		return formatConfigValue(value.Elem())
+	case reflect.Slice:
+		items := make([]string, value.Len())
+		for i := range items {
+			items[i] = formatConfigValue(value.Index(i))
+		}
+		return strings.Join(items, ",")
+	case reflect.Map:
+		items := make([]string, 0, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			items = append(items, formatConfigValue(iter.Key())+"="+formatConfigValue(iter.Value()))
+		}
+		sort.Strings(items)
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
@@ -0,0 +1,67 @@
+package application
+
+import (
+	"github.com/N-Vokhmyanin/go-framework/application/config"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testStructConfig struct {
+	Host    string         `env:"HOST" default:"rabbitmq" usage:"host"`
+	Enabled bool           `env:"ENABLED"`
+	Timeout time.Duration  `env:"TIMEOUT" default:"5s"`
+	Hosts   []string       `env:"HOSTS" default:"a, b"`
+	Weights map[string]int `env:"WEIGHTS"`
+	URL     url.URL        `env:"URL" default:"http://localhost:8080/path"`
+	IP      net.IP         `env:"IP" default:"127.0.0.1"`
+	Pool    testStructPool `prefix:"POOL_"`
+	Skipped string
+}
+
+type testStructPool struct {
+	Size int `env:"SIZE" default:"10"`
+}
+
+func Test_configSet_Struct(t *testing.T) {
+	cfg := newConfig()
+	var got testStructConfig
+	config.Struct(cfg.New("test"), &got)
+	cfg.Parse([]string{"-ENABLED", "-WEIGHTS=a=1,b=2", "-POOL_SIZE=20"})
+
+	want := testStructConfig{
+		Host:    "rabbitmq",
+		Enabled: true,
+		Timeout: 5 * time.Second,
+		Hosts:   []string{"a", "b"},
+		Weights: map[string]int{"a": 1, "b": 2},
+		URL:     url.URL{Scheme: "http", Host: "localhost:8080", Path: "/path"},
+		IP:      net.ParseIP("127.0.0.1"),
+		Pool:    testStructPool{Size: 20},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("configSet.Struct() = %+v, want %+v", got, want)
+	}
+
+	defaults := map[string]string{}
+	cfg.Visit(func(f contracts.ConfigFlag) {
+		defaults[f.Name()] = f.Default()
+	})
+	wantDefaults := map[string]string{
+		"HOST":      "rabbitmq",
+		"ENABLED":   "false",
+		"TIMEOUT":   "5s",
+		"HOSTS":     "a,b",
+		"WEIGHTS":   "",
+		"URL":       "http://localhost:8080/path",
+		"IP":        "127.0.0.1",
+		"POOL_SIZE": "10",
+		"config":    "",
+	}
+	if !reflect.DeepEqual(defaults, wantDefaults) {
+		t.Errorf("config defaults = %v, want %v", defaults, wantDefaults)
+	}
+}
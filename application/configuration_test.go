@@ -0,0 +1,253 @@
+package application
+
+import (
+	"errors"
+	"github.com/N-Vokhmyanin/go-framework/application/config"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_defaultConfig_ParseLayers(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "config.yaml")
+	envFile := filepath.Join(dir, ".env")
+	_ = os.WriteFile(yamlFile, []byte("test:\n  file: yaml\n  env: yaml\n  flag: yaml\n  dotenv: yaml\n"), 0o600)
+	_ = os.WriteFile(envFile, []byte("# comment\nTEST_DOTENV=\"dotenv\"\n"), 0o600)
+	t.Setenv("TEST_ENV", "env")
+	t.Setenv("TEST_FLAG", "env")
+
+	cfg := newConfig()
+	set := cfg.New("test")
+	var values [6]string
+	for i, name := range []string{"TEST_DEFAULT", "TEST_FILE", "TEST_DOTENV", "TEST_ENV", "TEST_FLAG", "TEST_CODE"} {
+		set.StringVar(&values[i], name, "default", "")
+	}
+	cfg.Parse([]string{"-config", yamlFile + "," + envFile, "-TEST_FLAG=flag", "app:env", "-out"})
+	if err := cfg.Set("TEST_CODE", "code"); err != nil {
+		t.Fatalf("config.Set() error = %v", err)
+	}
+
+	sources := map[string]string{}
+	cfg.Visit(func(f contracts.ConfigFlag) {
+		sources[f.Name()] = f.Value() + "@" + f.Source()
+	})
+	want := map[string]string{
+		"config":       "@default",
+		"TEST_DEFAULT": "default@default",
+		"TEST_FILE":    "yaml@file:" + yamlFile,
+		"TEST_DOTENV":  "dotenv@file:" + envFile,
+		"TEST_ENV":     "env@env",
+		"TEST_FLAG":    "flag@flag",
+		"TEST_CODE":    "code@code",
+	}
+	if !reflect.DeepEqual(sources, want) {
+		t.Errorf("config sources = %v, want %v", sources, want)
+	}
+	if got := cfg.Args(); !reflect.DeepEqual(got, []string{"app:env", "-out"}) {
+		t.Errorf("config.Args() = %v", got)
+	}
+}
+
+func Test_defaultConfig_ParseNamsralFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.conf")
+	_ = os.WriteFile(file, []byte("# namsral flag format\nTEST_HOST db.local\nTEST_PORT=5432\nTEST_DEBUG\nTEST_NAME  \"my app\"\n"), 0o600)
+
+	cfg := newConfig()
+	set := cfg.New("test")
+	var host, name string
+	var port int
+	var debug bool
+	set.StringVar(&host, "TEST_HOST", "", "")
+	set.IntVar(&port, "TEST_PORT", 0, "")
+	set.BoolVar(&debug, "TEST_DEBUG", false, "")
+	set.StringVar(&name, "TEST_NAME", "", "")
+	cfg.Parse([]string{"-config", file})
+
+	if host != "db.local" || port != 5432 || !debug || name != "my app" {
+		t.Errorf("config values = %q, %d, %t, %q, want db.local, 5432, true, my app", host, port, debug, name)
+	}
+}
+
+func Test_validateConfig(t *testing.T) {
+	type testConfig struct {
+		Host  string `env:"TEST_HOST" required:"true"`
+		Port  int    `env:"TEST_PORT" default:"80" min:"1" max:"65535"`
+		Level string `env:"TEST_LEVEL" default:"info" enum:"debug,info"`
+		Addr  string `env:"TEST_ADDR" format:"hostport"`
+	}
+
+	cfg := newConfig()
+	set := cfg.New("test")
+	config.Struct(set, &testConfig{})
+	var name string
+	set.StringVar(&name, "TEST_NAME", "", "")
+	config.Rules(set, "TEST_NAME", config.Pattern("^[a-z]+$"))
+
+	cfg.Parse([]string{"-TEST_PORT=0", "-TEST_LEVEL=trace", "-TEST_ADDR=localhost", "-TEST_NAME=App"})
+
+	err := validateConfig(cfg, nil, contracts.EnvDevelopment)
+	if err == nil {
+		t.Fatal("validateConfig() error = nil")
+	}
+	want := []string{
+		"config validation failed:",
+		`  - TEST_ADDR: value "localhost" must be a host:port address`,
+		"  - TEST_HOST: value is required",
+		`  - TEST_LEVEL: value "trace" must be one of: debug, info`,
+		`  - TEST_NAME: value "App" must match ^[a-z]+$`,
+		"  - TEST_PORT: value 0 must be at least 1",
+	}
+	if got := strings.Split(err.Error(), "\n"); !reflect.DeepEqual(got, want) {
+		t.Errorf("validateConfig() error = %q, want %q", got, want)
+	}
+
+	cfg.Parse([]string{"-TEST_HOST=db", "-TEST_PORT=5432", "-TEST_LEVEL=debug", "-TEST_ADDR=db:5432", "-TEST_NAME=app"})
+	if err = validateConfig(cfg, nil, contracts.EnvDevelopment); err != nil {
+		t.Errorf("validateConfig() error = %v", err)
+	}
+}
+
+func Test_defaultConfig_SecretFiles(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "db_pass")
+	_ = os.WriteFile(secretFile, []byte("s3cret\n"), 0o600)
+	t.Setenv("TEST_PASS_FILE", secretFile)
+
+	type testConfig struct {
+		Token string `env:"TEST_TOKEN" default:"token" secret:"true"`
+	}
+	cfg := newConfig()
+	set := cfg.New("test")
+	var pass, user string
+	config.SecretVar(set, &pass, "TEST_PASS", "password", "")
+	set.StringVar(&user, "TEST_USER", "user", "")
+	config.Struct(set, &testConfig{})
+	cfg.Parse(nil)
+
+	if pass != "s3cret" {
+		t.Errorf("secret value = %q, want %q", pass, "s3cret")
+	}
+	secrets := map[string]string{}
+	cfg.Visit(func(f contracts.ConfigFlag) {
+		if f.Secret() {
+			secrets[f.Name()] = f.Source()
+		}
+	})
+	want := map[string]string{"TEST_PASS": "file:" + secretFile, "TEST_TOKEN": "default"}
+	if !reflect.DeepEqual(secrets, want) {
+		t.Errorf("config secrets = %v, want %v", secrets, want)
+	}
+}
+
+func Test_defaultConfig_Reload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	_ = os.WriteFile(file, []byte("test:\n  level: info\n  port: 80\n"), 0o600)
+
+	cfg := newConfig()
+	set := cfg.New("test")
+	var level, code, host string
+	var port int
+	set.StringVar(&level, "TEST_LEVEL", "warn", "")
+	set.IntVar(&port, "TEST_PORT", 1, "")
+	set.StringVar(&code, "TEST_CODE", "", "")
+	set.StringVar(&host, "TEST_HOST", "localhost", "")
+	config.Rules(set, "TEST_PORT", config.Max(1000))
+	cfg.Parse([]string{"-config=" + file})
+	_ = cfg.Set("TEST_CODE", "code")
+	cfg.(*defaultConfig).validate = func() error {
+		if port == 13 {
+			return errors.New("unlucky port")
+		}
+		return nil
+	}
+
+	var changes []string
+	cfg.Subscribe("TEST_LEVEL", func(value string) {
+		changes = append(changes, "TEST_LEVEL="+value)
+	})
+	cfg.Subscribe("TEST_PORT", func(value string) {
+		changes = append(changes, "TEST_PORT="+value)
+	})
+
+	_ = os.WriteFile(file, []byte("test:\n  level: info\n  port: 100000\n"), 0o600)
+	if err := cfg.Reload(); err == nil || port != 80 {
+		t.Errorf("config.Reload() error = %v, port = %d, want rejected reload", err, port)
+	}
+
+	_ = os.WriteFile(file, []byte("test:\n  level: info\n  port: 13\n"), 0o600)
+	if err := cfg.Reload(); err == nil || port != 80 {
+		t.Errorf("config.Reload() error = %v, port = %d, want reload rejected by the validator", err, port)
+	}
+
+	_ = os.WriteFile(file, []byte("test:\n  port: 90\n  host: remote\n"), 0o600)
+	restart, err := cfg.(*defaultConfig).reloadAndNotify()
+	if err != nil {
+		t.Fatalf("config.Reload() error = %v", err)
+	}
+	want := []string{"TEST_LEVEL=warn", "TEST_PORT=90"}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("config changes = %v, want %v", changes, want)
+	}
+	if !reflect.DeepEqual(restart, []string{"TEST_HOST"}) {
+		t.Errorf("config restart required = %v, want [TEST_HOST]", restart)
+	}
+	if level != "warn" || port != 90 || code != "code" || host != "localhost" {
+		t.Errorf("config values = %s, %d, %s, %s", level, port, code, host)
+	}
+}
+
+func Test_defaultConfig_ParseProfiles(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	_ = os.WriteFile(file, []byte("app_env: production\ntest:\n  host: base\n  port: 80\n"), 0o600)
+	_ = os.WriteFile(filepath.Join(dir, "config.production.yaml"), []byte("test:\n  host: production\n"), 0o600)
+	_ = os.WriteFile(filepath.Join(dir, "config.staging.yaml"), []byte("test:\n  host: staging\n"), 0o600)
+
+	tests := []struct {
+		name      string
+		arguments []string
+		want      string
+	}{
+		{name: "profile from file", arguments: []string{"-config", file}, want: "production"},
+		{name: "profile from flag", arguments: []string{"-config", file, "-APP_ENV=staging"}, want: "staging"},
+		{name: "missing profile file", arguments: []string{"-config", file, "-APP_ENV=test"}, want: "base"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newConfig()
+			set := cfg.New("test")
+			var env, host string
+			var port int
+			set.StringVar(&env, appEnvFlagName, contracts.EnvDevelopment, "")
+			set.StringVar(&host, "TEST_HOST", "", "")
+			set.IntVar(&port, "TEST_PORT", 0, "")
+			cfg.Parse(tt.arguments)
+
+			if host != tt.want || port != 80 {
+				t.Errorf("config values = %s, %d, want %s, 80", host, port, tt.want)
+			}
+		})
+	}
+}
+
+func Test_profileConfigFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "config.yaml", want: "config.production.yaml"},
+		{path: "/etc/app/config.json", want: "/etc/app/config.production.json"},
+		{path: ".env", want: ".env.production"},
+		{path: "/etc/app/.env", want: "/etc/app/.env.production"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := profileConfigFile(tt.path, contracts.EnvProduction); got != tt.want {
+				t.Errorf("profileConfigFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/logger"
+	"go.uber.org/zap"
+	"maps"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// configWatcher reloads the config on SIGHUP and when config or secret files change.
+type configWatcher struct {
+	config   *defaultConfig
+	interval time.Duration
+	log      logger.Logger
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+var _ contracts.CanStartContext = (*configWatcher)(nil)
+var _ contracts.CanStopContext = (*configWatcher)(nil)
+
+// newConfigWatcher creates a watcher polling files every interval, zero interval disables polling.
+func newConfigWatcher(config *defaultConfig, interval time.Duration, log logger.Logger) *configWatcher {
+	return &configWatcher{
+		config:   config,
+		interval: interval,
+		log:      log.With(logger.WithComponent, "config"),
+	}
+}
+
+func (w *configWatcher) StartService(context.Context) error {
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go w.watch(hup)
+	return nil
+}
+
+func (w *configWatcher) StopService(ctx context.Context) error {
+	if w.stop == nil {
+		return nil
+	}
+	close(w.stop)
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *configWatcher) watch(hup chan os.Signal) {
+	defer close(w.done)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if w.interval > 0 {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	versions := fileVersions(w.config.watchedFiles())
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-hup:
+			w.log.Infow("config reload requested", "signal", "SIGHUP")
+		case <-tick:
+			if maps.Equal(versions, fileVersions(w.config.watchedFiles())) {
+				continue
+			}
+			w.log.Infow("config files changed")
+		}
+
+		restart, err := w.config.reloadAndNotify()
+		if err != nil {
+			w.log.Errorw("config reload failed, previous values are kept", zap.Error(err))
+		} else {
+			w.log.Infow("config reloaded")
+		}
+		if len(restart) > 0 {
+			w.log.Warnw("config values changed, restart required to apply them", "names", restart)
+		}
+		versions = fileVersions(w.config.watchedFiles())
+	}
+}
+
+// fileVersions returns modification times and sizes of the files, missing files have empty versions.
+func fileVersions(files []string) map[string]string {
+	versions := make(map[string]string, len(files))
+	for _, file := range files {
+		versions[file] = ""
+		if info, err := os.Stat(file); err == nil {
+			versions[file] = fmt.Sprintf("%s/%d", info.ModTime(), info.Size())
+		}
+	}
+	return versions
+}
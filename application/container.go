@@ -1,112 +1,489 @@
 package application
 
 import (
+	"fmt"
 	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/errors"
 	"reflect"
+	"runtime"
+	"strings"
+	"sync"
 )
 
+var optionalDependencyType = reflect.TypeOf((*contracts.OptionalDependency)(nil)).Elem()
+
+type lifetime int
+
+const (
+	lifetimeTransient lifetime = iota
+	lifetimeSingleton
+	lifetimeScoped
+)
+
+func (l lifetime) String() string {
+	switch l {
+	case lifetimeSingleton:
+		return "singleton"
+	case lifetimeScoped:
+		return "scoped"
+	default:
+		return "transient"
+	}
+}
+
 type container struct {
-	binds map[reflect.Type]*binding
+	sync.RWMutex
+	binds      map[bindingKey]*binding
+	tags       map[string][]*binding
+	order      []*binding // bindings in registration order
+	duplicates []string   // unnamed abstractions registered more than once
+	provider   string     // provider registering bindings at the moment
+}
+
+// MissingDependencyError is raised when a required resolver argument has no binding.
+type MissingDependencyError struct {
+	Dependency string
+	Path       []string
+}
+
+func (e *MissingDependencyError) Error() string {
+	path := append(append([]string{}, e.Path...), e.Dependency)
+	return fmt.Sprintf("missing binding for %s, resolution path: %s", e.Dependency, strings.Join(path, " -> "))
+}
+
+type bindingKey struct {
+	abstraction reflect.Type
+	name        string
+}
+
+func (k bindingKey) String() string {
+	if k.name == "" {
+		return k.abstraction.String()
+	}
+	return fmt.Sprintf("%s(%s)", k.abstraction.String(), k.name)
 }
 
 type binding struct {
-	singleton bool
-	resolver  interface{} // resolver function
-	instance  interface{} // instance stored for singleton bindings
+	sync.Mutex   // guards singleton construction
+	key          bindingKey
+	lifetime     lifetime
+	resolver     interface{}    // resolver function
+	instance     interface{}    // instance stored for singleton bindings
+	dependencies []reflect.Type // resolver arguments
+	provider     string         // provider which registered the binding
+}
+
+// resolution holds the state of one resolving call chain.
+type resolution struct {
+	scope *scope
+	path  []string   // human-readable resolution path
+	stack []*binding // bindings being resolved
 }
 
 var _ contracts.Container = (*container)(nil)
 
-func newContainer() contracts.Container {
+func newContainer() *container {
 	return &container{
-		binds: map[reflect.Type]*binding{},
+		binds: map[bindingKey]*binding{},
+		tags:  map[string][]*binding{},
 	}
 }
 
-func (c *container) invoke(function interface{}) interface{} {
-	return reflect.ValueOf(function).Call(c.arguments(function))[0].Interface()
-}
-
-func (c *container) bind(resolver interface{}, singleton bool) {
-	if reflect.TypeOf(resolver).Kind() != reflect.Func {
+func (c *container) newBindings(resolver interface{}, name string, lifetime lifetime) []*binding {
+	resolverType := reflect.TypeOf(resolver)
+	if resolverType == nil || resolverType.Kind() != reflect.Func {
 		panic("the resolver must be a function")
 	}
 
-	for i := 0; i < reflect.TypeOf(resolver).NumOut(); i++ {
-		var instance interface{}
-		c.binds[reflect.TypeOf(resolver).Out(i)] = &binding{
-			singleton: singleton,
-			resolver:  resolver,
-			instance:  instance,
+	dependencies := make([]reflect.Type, resolverType.NumIn())
+	for i := range dependencies {
+		dependencies[i] = resolverType.In(i)
+	}
+
+	bindings := make([]*binding, resolverType.NumOut())
+	for i := range bindings {
+		bindings[i] = &binding{
+			key:          bindingKey{abstraction: resolverType.Out(i), name: name},
+			lifetime:     lifetime,
+			resolver:     resolver,
+			dependencies: dependencies,
 		}
 	}
+	return bindings
 }
 
-func (c *container) arguments(function interface{}) []reflect.Value {
+func (c *container) bind(resolver interface{}, name string, lifetime lifetime) {
+	bindings := c.newBindings(resolver, name, lifetime)
+
+	c.Lock()
+	defer c.Unlock()
+
+	for _, bind := range bindings {
+		bind.provider = c.provider
+		if previous, ok := c.binds[bind.key]; ok {
+			if name == "" {
+				c.duplicates = append(c.duplicates, bind.key.String())
+			}
+			for i := range c.order {
+				if c.order[i] == previous {
+					c.order[i] = bind
+				}
+			}
+		} else {
+			c.order = append(c.order, bind)
+		}
+		c.binds[bind.key] = bind
+	}
+}
+
+func (c *container) lookup(key bindingKey) (*binding, bool) {
+	c.RLock()
+	defer c.RUnlock()
+
+	bind, ok := c.binds[key]
+	return bind, ok
+}
+
+func (c *container) arguments(r *resolution, function interface{}) []reflect.Value {
 	argumentsCount := reflect.TypeOf(function).NumIn()
 	arguments := make([]reflect.Value, argumentsCount)
 
 	for i := 0; i < argumentsCount; i++ {
-		abstraction := reflect.TypeOf(function).In(i)
+		arguments[i] = c.argument(r, reflect.TypeOf(function).In(i))
+	}
 
-		if concrete, ok := c.binds[abstraction]; ok {
-			arguments[i] = reflect.ValueOf(c.resolve(concrete))
-		} else {
-			arguments[i] = reflect.New(abstraction).Elem()
+	return arguments
+}
+
+func (c *container) argument(r *resolution, abstraction reflect.Type) reflect.Value {
+	if concrete, ok := c.lookup(bindingKey{abstraction: abstraction}); ok {
+		if instance := c.resolve(r, concrete); instance != nil {
+			return reflect.ValueOf(instance)
 		}
+		return reflect.New(abstraction).Elem()
 	}
 
-	return arguments
+	if abstraction.Implements(optionalDependencyType) {
+		optional := reflect.New(abstraction)
+		dependency := optional.Elem().Interface().(contracts.OptionalDependency).DependencyType()
+		if concrete, ok := c.lookup(bindingKey{abstraction: dependency}); ok {
+			optional.Interface().(contracts.OptionalDependencySetter).SetDependency(c.resolve(r, concrete))
+		}
+		return optional.Elem()
+	}
+
+	panic(&MissingDependencyError{
+		Dependency: abstraction.String(),
+		Path:       append([]string{}, r.path...),
+	})
+}
+
+// canResolve reports whether the argument has a binding or is optional.
+func (c *container) canResolve(abstraction reflect.Type) bool {
+	if _, ok := c.binds[bindingKey{abstraction: abstraction}]; ok {
+		return true
+	}
+	return abstraction.Implements(optionalDependencyType)
+}
+
+// dependencyBinding returns the binding an argument refers to, di.Optional arguments refer
+// to the binding of their dependency type, must be called with the container lock held.
+func (c *container) dependencyBinding(abstraction reflect.Type) (*binding, bool) {
+	if bind, ok := c.binds[bindingKey{abstraction: abstraction}]; ok {
+		return bind, true
+	}
+	if abstraction.Implements(optionalDependencyType) {
+		dependency := reflect.New(abstraction).Elem().Interface().(contracts.OptionalDependency).DependencyType()
+		bind, ok := c.binds[bindingKey{abstraction: dependency}]
+		return bind, ok
+	}
+	return nil, false
+}
+
+// Validate checks the whole graph: every resolver argument must be bound or optional,
+// singletons must not depend on scoped bindings and bindings must not depend on each other cyclically.
+func (c *container) Validate() (err error) {
+	errs := errors.NewMultiError()
+
+	c.RLock()
+	for _, bind := range c.order {
+		for _, dependency := range bind.dependencies {
+			if !c.canResolve(dependency) {
+				errs.Append(&MissingDependencyError{
+					Dependency: dependency.String(),
+					Path:       []string{bind.key.String()},
+				})
+				continue
+			}
+			dependencyBind, ok := c.dependencyBinding(dependency)
+			if ok && bind.lifetime == lifetimeSingleton && dependencyBind.lifetime == lifetimeScoped {
+				errs.Append(fmt.Errorf("singleton %s depends on scoped %s", bind.key, dependencyBind.key))
+			}
+		}
+	}
+	c.RUnlock()
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				if cycleErr, ok := r.(error); ok {
+					errs.Append(cycleErr)
+				} else {
+					panic(r)
+				}
+			}
+		}()
+		c.sorted()
+	}()
+
+	return errs.ErrorOrNil()
+}
+
+// funcLocation returns the source location of the function.
+func funcLocation(function interface{}) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(function).Pointer())
+	if fn == nil {
+		return reflect.TypeOf(function).String()
+	}
+	file, line := fn.FileLine(fn.Entry())
+	return fmt.Sprintf("%s:%d", file, line)
 }
 
-func (c *container) resolve(concrete *binding) interface{} {
+func (c *container) resolve(r *resolution, concrete *binding) interface{} {
+	for _, active := range r.stack {
+		if active == concrete {
+			panic(newCycleError(r.stack, concrete))
+		}
+	}
+
+	switch concrete.lifetime {
+	case lifetimeSingleton:
+		return c.resolveSingleton(r, concrete)
+	case lifetimeScoped:
+		if r.scope == nil {
+			panic(fmt.Errorf(
+				"scoped binding %s resolved outside of a scope, resolution path: %s",
+				concrete.key,
+				strings.Join(append(append([]string{}, r.path...), concrete.key.String()), " -> "),
+			))
+		}
+		return r.scope.resolve(r, concrete)
+	default:
+		return c.invoke(r, concrete)
+	}
+}
+
+func (c *container) resolveSingleton(r *resolution, concrete *binding) interface{} {
+	concrete.Lock()
+	defer concrete.Unlock()
+
 	if concrete.instance != nil {
 		return concrete.instance
 	}
-	instance := c.invoke(concrete.resolver)
-	if concrete.singleton {
-		concrete.instance = instance
+
+	// singletons never capture scoped instances
+	scope := r.scope
+	r.scope = nil
+	defer func() { r.scope = scope }()
+
+	concrete.instance = c.invoke(r, concrete)
+	return concrete.instance
+}
+
+func (c *container) invoke(r *resolution, concrete *binding) interface{} {
+	r.path = append(r.path, concrete.key.String())
+	r.stack = append(r.stack, concrete)
+	defer func() {
+		r.path = r.path[:len(r.path)-1]
+		r.stack = r.stack[:len(r.stack)-1]
+	}()
+
+	return reflect.ValueOf(concrete.resolver).Call(c.arguments(r, concrete.resolver))[0].Interface()
+}
+
+// sorted returns bindings in dependency order: each binding goes after the bindings
+// its resolver arguments refer to, ties are broken by registration order.
+func (c *container) sorted() []*binding {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	c.RLock()
+	defer c.RUnlock()
+
+	state := make(map[*binding]int, len(c.order))
+	sorted := make([]*binding, 0, len(c.order))
+	var path []*binding
+
+	var visit func(bind *binding)
+	visit = func(bind *binding) {
+		switch state[bind] {
+		case visited:
+			return
+		case visiting:
+			panic(newCycleError(path, bind))
+		case unvisited:
+		}
+
+		state[bind] = visiting
+		path = append(path, bind)
+		for _, dependency := range bind.dependencies {
+			if dependencyBind, ok := c.dependencyBinding(dependency); ok {
+				visit(dependencyBind)
+			}
+		}
+		path = path[:len(path)-1]
+		state[bind] = visited
+
+		sorted = append(sorted, bind)
+	}
+
+	for _, bind := range c.order {
+		visit(bind)
 	}
-	return instance
+
+	return sorted
 }
 
+func newCycleError(path []*binding, bind *binding) error {
+	var cycle []string
+	for i := len(path) - 1; i >= 0; i-- {
+		cycle = append([]string{path[i].key.String()}, cycle...)
+		if path[i] == bind {
+			break
+		}
+	}
+	cycle = append(cycle, bind.key.String())
+	return fmt.Errorf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
+}
+
+// Instances returns singleton instances in dependency order,
+// an instance bound to several abstractions is returned once.
 func (c *container) Instances() []interface{} {
 	var instances []interface{}
-	for _, bind := range c.binds {
-		if bind.singleton {
-			instance := c.resolve(bind)
-			if reflect.TypeOf(instance).Kind() != reflect.Func {
-				instances = append(instances, instance)
+	seen := make(map[interface{}]bool)
+	for _, bind := range c.sorted() {
+		if bind.lifetime == lifetimeSingleton {
+			instance := c.resolve(&resolution{}, bind)
+			if instance == nil || reflect.TypeOf(instance).Kind() == reflect.Func {
+				continue
 			}
+			if reflect.TypeOf(instance).Comparable() {
+				if seen[instance] {
+					continue
+				}
+				seen[instance] = true
+			}
+			instances = append(instances, instance)
 		}
 	}
 	return instances
 }
 
+// override binds the singleton resolver replacing existing bindings without reporting them as duplicates.
+func (c *container) override(resolver interface{}) {
+	duplicates := len(c.Duplicates())
+	c.bind(resolver, "", lifetimeSingleton)
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.duplicates = c.duplicates[:duplicates]
+}
+
+// setProvider marks bindings registered from now on as registered by the provider.
+func (c *container) setProvider(provider string) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.provider = provider
+}
+
+// Duplicates returns unnamed abstractions whose binding was overwritten by a later registration.
+func (c *container) Duplicates() []string {
+	c.RLock()
+	defer c.RUnlock()
+
+	return append([]string{}, c.duplicates...)
+}
+
 func (c *container) Singleton(resolver interface{}) {
-	c.bind(resolver, true)
+	c.bind(resolver, "", lifetimeSingleton)
 }
 
 func (c *container) Transient(resolver interface{}) {
-	c.bind(resolver, false)
+	c.bind(resolver, "", lifetimeTransient)
+}
+
+func (c *container) Scoped(resolver interface{}) {
+	c.bind(resolver, "", lifetimeScoped)
+}
+
+func (c *container) SingletonNamed(name string, resolver interface{}) {
+	if name == "" {
+		panic("the binding name must not be empty")
+	}
+	c.bind(resolver, name, lifetimeSingleton)
+}
+
+func (c *container) TransientNamed(name string, resolver interface{}) {
+	if name == "" {
+		panic("the binding name must not be empty")
+	}
+	c.bind(resolver, name, lifetimeTransient)
+}
+
+func (c *container) Tag(tag string, resolver interface{}) {
+	bindings := c.newBindings(resolver, "", lifetimeSingleton)
+
+	c.Lock()
+	defer c.Unlock()
+
+	for _, bind := range bindings {
+		bind.provider = c.provider
+		bind.key.name = fmt.Sprintf("#%s-%d", tag, len(c.tags[tag]))
+		c.tags[tag] = append(c.tags[tag], bind)
+		c.order = append(c.order, bind)
+	}
+}
+
+func (c *container) NewScope() contracts.Scope {
+	return newScope(c)
 }
 
-func (c *container) Make(receiver interface{}) (res []interface{}) {
+func (c *container) Make(receiver interface{}) []interface{} {
+	return c.make(&resolution{}, receiver)
+}
+
+func (c *container) MakeNamed(name string, receiver interface{}) {
+	c.makeNamed(&resolution{}, name, receiver)
+}
+
+func (c *container) MakeTagged(tag string) []interface{} {
+	return c.makeTagged(&resolution{}, tag)
+}
+
+func (c *container) Inject(target interface{}) {
+	c.inject(&resolution{}, target)
+}
+
+func (c *container) make(r *resolution, receiver interface{}) (res []interface{}) {
 	if reflect.TypeOf(receiver) == nil {
 		panic("cannot detect type of the receiver, make sure your are passing reference of the object")
 	}
 
 	if reflect.TypeOf(receiver).Kind() == reflect.Ptr {
-		abstraction := reflect.TypeOf(receiver).Elem()
-		if concrete, ok := c.binds[abstraction]; ok {
-			reflect.ValueOf(receiver).Elem().Set(reflect.ValueOf(c.resolve(concrete)))
-			return nil
-		}
-		panic("no concrete found for the abstraction " + abstraction.String())
+		c.makeNamed(r, "", receiver)
+		return nil
 	}
 
 	if reflect.TypeOf(receiver).Kind() == reflect.Func {
-		arguments := c.arguments(receiver)
+		r.path = append(r.path, funcLocation(receiver))
+		arguments := c.arguments(r, receiver)
+		r.path = r.path[:len(r.path)-1]
+
 		values := reflect.ValueOf(receiver).Call(arguments)
 		for _, value := range values {
 			if value.CanInterface() {
@@ -118,3 +495,65 @@ func (c *container) Make(receiver interface{}) (res []interface{}) {
 
 	panic("the receiver must be either a reference or a callback")
 }
+
+func (c *container) makeNamed(r *resolution, name string, receiver interface{}) {
+	if reflect.TypeOf(receiver) == nil || reflect.TypeOf(receiver).Kind() != reflect.Ptr {
+		panic("the receiver must be a reference")
+	}
+
+	key := bindingKey{abstraction: reflect.TypeOf(receiver).Elem(), name: name}
+	if concrete, ok := c.lookup(key); ok {
+		if instance := c.resolve(r, concrete); instance != nil {
+			reflect.ValueOf(receiver).Elem().Set(reflect.ValueOf(instance))
+		}
+		return
+	}
+	panic(&MissingDependencyError{
+		Dependency: key.String(),
+		Path:       append([]string{}, r.path...),
+	})
+}
+
+func (c *container) makeTagged(r *resolution, tag string) []interface{} {
+	c.RLock()
+	tagged := append([]*binding{}, c.tags[tag]...)
+	c.RUnlock()
+
+	instances := make([]interface{}, 0, len(tagged))
+	for _, bind := range c.sorted() {
+		for _, taggedBind := range tagged {
+			if bind == taggedBind {
+				instances = append(instances, c.resolve(r, bind))
+			}
+		}
+	}
+	return instances
+}
+
+// inject fills exported struct fields tagged with `inject:""`, a non-empty tag value selects a named binding.
+func (c *container) inject(r *resolution, target interface{}) {
+	value := reflect.ValueOf(target)
+	if !value.IsValid() || value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		panic("the target must be a reference to a struct")
+	}
+
+	value = value.Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name, ok := field.Tag.Lookup("inject")
+		if !ok {
+			continue
+		}
+		if !field.IsExported() {
+			panic(fmt.Sprintf("the injected field %s.%s must be exported", value.Type(), field.Name))
+		}
+
+		r.path = append(r.path, value.Type().String()+"."+field.Name)
+		if name == "" {
+			value.Field(i).Set(c.argument(r, field.Type))
+		} else {
+			c.makeNamed(r, name, value.Field(i).Addr().Interface())
+		}
+		r.path = r.path[:len(r.path)-1]
+	}
+}
@@ -0,0 +1,221 @@
+package application
+
+import (
+	"fmt"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	healthContracts "github.com/N-Vokhmyanin/go-framework/health/contracts"
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/urfave/cli/v2"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// lifecycleInterfaces are reported by app:container when the bound instance implements them.
+var lifecycleInterfaces = []struct {
+	name string
+	typ  reflect.Type
+}{
+	{"CanBoot", reflect.TypeOf((*contracts.CanBoot)(nil)).Elem()},
+	{"CanBootContext", reflect.TypeOf((*contracts.CanBootContext)(nil)).Elem()},
+	{"CanInit", reflect.TypeOf((*contracts.CanInit)(nil)).Elem()},
+	{"CanInitContext", reflect.TypeOf((*contracts.CanInitContext)(nil)).Elem()},
+	{"CanStart", reflect.TypeOf((*contracts.CanStart)(nil)).Elem()},
+	{"CanStartContext", reflect.TypeOf((*contracts.CanStartContext)(nil)).Elem()},
+	{"CanStop", reflect.TypeOf((*contracts.CanStop)(nil)).Elem()},
+	{"CanStopContext", reflect.TypeOf((*contracts.CanStopContext)(nil)).Elem()},
+	{"health.Service", reflect.TypeOf((*healthContracts.Service)(nil)).Elem()},
+	{"health.Drainer", reflect.TypeOf((*healthContracts.Drainer)(nil)).Elem()},
+}
+
+type bindingDependency struct {
+	key      bindingKey
+	optional bool
+	missing  bool
+}
+
+type bindingInfo struct {
+	key          bindingKey
+	lifetime     lifetime
+	provider     string
+	resolver     string
+	dependencies []bindingDependency
+	implements   []string
+}
+
+type containerCommand struct {
+	container *container
+}
+
+func newContainerCommand(c *container) *cli.Command {
+	cmd := &containerCommand{container: c}
+	return &cli.Command{
+		Category: "app",
+		Name:     "app:container",
+		Usage:    "Print all container bindings",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "graph",
+				Usage: "Graph variant (dot, mermaid)",
+			},
+		},
+		Action: cmd.appContainer,
+	}
+}
+
+func (c *containerCommand) appContainer(ctx *cli.Context) error {
+	infos := c.container.bindingInfos()
+
+	switch ctx.String("graph") {
+	case "":
+		printBindingsTable(os.Stdout, infos)
+	case "dot":
+		printBindingsDot(os.Stdout, infos)
+	case "mermaid":
+		printBindingsMermaid(os.Stdout, infos)
+	default:
+		return fmt.Errorf("unknown graph variant: %s", ctx.String("graph"))
+	}
+	return nil
+}
+
+// bindingInfos describes bindings in dependency order.
+func (c *container) bindingInfos() []bindingInfo {
+	sorted := c.sorted()
+	infos := make([]bindingInfo, 0, len(sorted))
+	for _, bind := range sorted {
+		info := bindingInfo{
+			key:      bind.key,
+			lifetime: bind.lifetime,
+			provider: bind.provider,
+			resolver: funcLocation(bind.resolver),
+		}
+
+		for _, dependency := range bind.dependencies {
+			item := bindingDependency{key: bindingKey{abstraction: dependency}}
+			if _, ok := c.lookup(item.key); !ok && dependency.Implements(optionalDependencyType) {
+				optional := reflect.New(dependency).Elem().Interface().(contracts.OptionalDependency)
+				item.key.abstraction = optional.DependencyType()
+				item.optional = true
+			}
+			_, ok := c.lookup(item.key)
+			item.missing = !ok
+			info.dependencies = append(info.dependencies, item)
+		}
+
+		implementation := bind.key.abstraction
+		if bind.lifetime == lifetimeSingleton {
+			if instance := c.resolve(&resolution{}, bind); instance != nil {
+				implementation = reflect.TypeOf(instance)
+			}
+		}
+		for _, iface := range lifecycleInterfaces {
+			if implementation.Implements(iface.typ) {
+				info.implements = append(info.implements, iface.name)
+			}
+		}
+
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+func (d bindingDependency) String() string {
+	switch {
+	case d.missing && d.optional:
+		return d.key.String() + " (optional, missing)"
+	case d.missing:
+		return d.key.String() + " (missing)"
+	case d.optional:
+		return d.key.String() + " (optional)"
+	default:
+		return d.key.String()
+	}
+}
+
+func printBindingsTable(w io.Writer, infos []bindingInfo) {
+	t := table.NewWriter()
+	t.SetOutputMirror(w)
+	t.AppendHeader(table.Row{"Type", "Name", "Lifetime", "Provider", "Resolver", "Dependencies", "Implements"})
+	for _, info := range infos {
+		dependencies := make([]string, 0, len(info.dependencies))
+		for _, dependency := range info.dependencies {
+			dependencies = append(dependencies, dependency.String())
+		}
+		t.AppendRow(table.Row{
+			info.key.abstraction.String(),
+			info.key.name,
+			info.lifetime.String(),
+			info.provider,
+			info.resolver,
+			strings.Join(dependencies, "\n"),
+			strings.Join(info.implements, ", "),
+		})
+	}
+	t.Render()
+}
+
+// bindingNodes assigns graph node ids, type names alone are ambiguous across packages.
+type bindingNodes map[bindingKey]string
+
+func (n bindingNodes) id(key bindingKey) string {
+	if _, ok := n[key]; !ok {
+		n[key] = fmt.Sprintf("n%d", len(n))
+	}
+	return n[key]
+}
+
+func printBindingsDot(w io.Writer, infos []bindingInfo) {
+	nodes := bindingNodes{}
+
+	_, _ = fmt.Fprintln(w, "digraph container {")
+	_, _ = fmt.Fprintln(w, "  rankdir=LR;")
+	for _, info := range infos {
+		_, _ = fmt.Fprintf(w, "  %s [label=%q];\n", nodes.id(info.key), info.key.String()+"\n"+info.lifetime.String())
+	}
+	for _, info := range infos {
+		for _, dependency := range info.dependencies {
+			var attrs []string
+			if _, ok := nodes[dependency.key]; !ok {
+				_, _ = fmt.Fprintf(w, "  %s [label=%q, color=red];\n", nodes.id(dependency.key), dependency.key.String()+"\nmissing")
+			}
+			if dependency.optional {
+				attrs = append(attrs, "style=dashed")
+			}
+			if dependency.missing {
+				attrs = append(attrs, "color=red")
+			}
+			edge := fmt.Sprintf("  %s -> %s", nodes.id(info.key), nodes.id(dependency.key))
+			if len(attrs) > 0 {
+				edge += " [" + strings.Join(attrs, ", ") + "]"
+			}
+			_, _ = fmt.Fprintln(w, edge+";")
+		}
+	}
+	_, _ = fmt.Fprintln(w, "}")
+}
+
+func printBindingsMermaid(w io.Writer, infos []bindingInfo) {
+	nodes := bindingNodes{}
+	label := func(text string) string {
+		return strings.ReplaceAll(text, `"`, "#quot;")
+	}
+
+	_, _ = fmt.Fprintln(w, "graph LR")
+	for _, info := range infos {
+		_, _ = fmt.Fprintf(w, "  %s[\"%s<br/>%s\"]\n", nodes.id(info.key), label(info.key.String()), info.lifetime.String())
+	}
+	for _, info := range infos {
+		for _, dependency := range info.dependencies {
+			if _, ok := nodes[dependency.key]; !ok {
+				_, _ = fmt.Fprintf(w, "  %s[\"%s<br/>missing\"]\n", nodes.id(dependency.key), label(dependency.key.String()))
+			}
+			arrow := "-->"
+			if dependency.optional {
+				arrow = "-.->"
+			}
+			_, _ = fmt.Fprintf(w, "  %s %s %s\n", nodes.id(info.key), arrow, nodes.id(dependency.key))
+		}
+	}
+}
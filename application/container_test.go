@@ -0,0 +1,349 @@
+package application
+
+import (
+	"github.com/N-Vokhmyanin/go-framework/utils/di"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type testDatabase struct{ name string }
+type testRepository struct{ db *testDatabase }
+type testServer struct{ repo *testRepository }
+
+type testClosable struct{ closed bool }
+
+func (s *testClosable) Close() { s.closed = true }
+
+type testCycleA struct{}
+type testCycleB struct{}
+
+func Test_container_InstancesOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		resolvers []interface{}
+		want      []reflect.Type
+	}{
+		{
+			name: "registered in dependency order",
+			resolvers: []interface{}{
+				func() *testDatabase { return &testDatabase{} },
+				func(db *testDatabase) *testRepository { return &testRepository{db} },
+				func(repo *testRepository) *testServer { return &testServer{repo} },
+			},
+			want: []reflect.Type{
+				reflect.TypeOf(&testDatabase{}),
+				reflect.TypeOf(&testRepository{}),
+				reflect.TypeOf(&testServer{}),
+			},
+		},
+		{
+			name: "registered in reverse order",
+			resolvers: []interface{}{
+				func(repo *testRepository) *testServer { return &testServer{repo} },
+				func(db *testDatabase) *testRepository { return &testRepository{db} },
+				func() *testDatabase { return &testDatabase{} },
+			},
+			want: []reflect.Type{
+				reflect.TypeOf(&testDatabase{}),
+				reflect.TypeOf(&testRepository{}),
+				reflect.TypeOf(&testServer{}),
+			},
+		},
+		{
+			name: "optional dependency registered in reverse order",
+			resolvers: []interface{}{
+				func(repo di.Optional[*testRepository]) *testServer { return &testServer{repo.Value()} },
+				func() *testRepository { return &testRepository{} },
+			},
+			want: []reflect.Type{
+				reflect.TypeOf(&testRepository{}),
+				reflect.TypeOf(&testServer{}),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				c := newContainer()
+				for _, resolver := range tt.resolvers {
+					c.Singleton(resolver)
+				}
+				var got []reflect.Type
+				for _, instance := range c.Instances() {
+					got = append(got, reflect.TypeOf(instance))
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("container.Instances() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func Test_container_InstancesUnique(t *testing.T) {
+	c := newContainer()
+	c.Singleton(func() *testDatabase { return &testDatabase{} })
+	c.Singleton(func(db *testDatabase) any { return db })
+
+	if got := len(c.Instances()); got != 1 {
+		t.Errorf("len(container.Instances()) = %v, want %v", got, 1)
+	}
+}
+
+func Test_container_Cycle(t *testing.T) {
+	c := newContainer()
+	c.Singleton(func(*testCycleB) *testCycleA { return &testCycleA{} })
+	c.Singleton(func(*testCycleA) *testCycleB { return &testCycleB{} })
+
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if !ok {
+			t.Fatalf("container.Instances() panic = %v, want cycle error", r)
+		}
+		want := "*application.testCycleA -> *application.testCycleB -> *application.testCycleA"
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("container.Instances() panic = %v, want %v", err, want)
+		}
+	}()
+	c.Instances()
+}
+
+func Test_container_ValidateOptional(t *testing.T) {
+	c := newContainer()
+	c.Singleton(func(di.Optional[*testCycleB]) *testCycleA { return &testCycleA{} })
+	c.Singleton(func(*testCycleA) *testCycleB { return &testCycleB{} })
+	c.Scoped(func() *testDatabase { return &testDatabase{} })
+	c.Singleton(func(di.Optional[*testDatabase]) *testRepository { return &testRepository{} })
+
+	err := c.Validate()
+	if err == nil {
+		t.Fatal("container.Validate() error = nil")
+	}
+	for _, want := range []string{
+		"*application.testCycleA -> *application.testCycleB -> *application.testCycleA",
+		"singleton *application.testRepository depends on scoped *application.testDatabase",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("container.Validate() error = %v, want %v", err, want)
+		}
+	}
+}
+
+func Test_container_Named(t *testing.T) {
+	c := newContainer()
+	c.Singleton(func() *testDatabase { return &testDatabase{name: "default"} })
+	c.SingletonNamed("analytics", func() *testDatabase { return &testDatabase{name: "analytics"} })
+
+	tests := []struct {
+		name    string
+		binding string
+		want    string
+	}{
+		{
+			name:    "unnamed binding",
+			binding: "",
+			want:    "default",
+		},
+		{
+			name:    "named binding",
+			binding: "analytics",
+			want:    "analytics",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *testDatabase
+			c.MakeNamed(tt.binding, &got)
+			if got.name != tt.want {
+				t.Errorf("container.MakeNamed() = %v, want %v", got.name, tt.want)
+			}
+		})
+	}
+	if got := len(c.Duplicates()); got != 0 {
+		t.Errorf("len(container.Duplicates()) = %v, want %v", got, 0)
+	}
+}
+
+func Test_container_Tagged(t *testing.T) {
+	c := newContainer()
+	c.Singleton(func() *testDatabase { return &testDatabase{name: "default"} })
+	c.Tag("databases", func() *testDatabase { return &testDatabase{name: "first"} })
+	c.Tag("databases", func() *testDatabase { return &testDatabase{name: "second"} })
+
+	var got []string
+	for _, instance := range c.MakeTagged("databases") {
+		got = append(got, instance.(*testDatabase).name)
+	}
+	want := []string{"first", "second"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("container.MakeTagged() = %v, want %v", got, want)
+	}
+}
+
+func Test_container_Duplicates(t *testing.T) {
+	c := newContainer()
+	c.Singleton(func() *testDatabase { return &testDatabase{name: "first"} })
+	c.Singleton(func() *testDatabase { return &testDatabase{name: "second"} })
+
+	var got *testDatabase
+	c.Make(&got)
+	if got.name != "second" {
+		t.Errorf("container.Make() = %v, want %v", got.name, "second")
+	}
+	if want := []string{"*application.testDatabase"}; !reflect.DeepEqual(c.Duplicates(), want) {
+		t.Errorf("container.Duplicates() = %v, want %v", c.Duplicates(), want)
+	}
+}
+
+func Test_container_Optional(t *testing.T) {
+	c := newContainer()
+	c.Singleton(func() *testDatabase { return &testDatabase{name: "default"} })
+
+	c.Make(func(db di.Optional[*testDatabase], server di.Optional[*testServer]) {
+		if got, ok := db.Get(); !ok || got.name != "default" {
+			t.Errorf("optional bound dependency = %v, %v, want %v", got, ok, "default")
+		}
+		if server.Ok() {
+			t.Errorf("optional missing dependency is present")
+		}
+	})
+}
+
+func Test_container_Missing(t *testing.T) {
+	c := newContainer()
+	c.Singleton(func(repo *testRepository) *testServer { return &testServer{repo} })
+	c.Singleton(func(db *testDatabase) *testRepository { return &testRepository{db} })
+
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "*application.testDatabase") {
+		t.Errorf("container.Validate() error = %v, want missing *application.testDatabase", err)
+	}
+
+	defer func() {
+		err, ok := recover().(*MissingDependencyError)
+		if !ok {
+			t.Fatalf("container.Make() panic = %v, want MissingDependencyError", err)
+		}
+		want := []string{"*application.testServer", "*application.testRepository"}
+		if !reflect.DeepEqual(err.Path[1:], want) || err.Dependency != "*application.testDatabase" {
+			t.Errorf("container.Make() panic = %v", err)
+		}
+	}()
+	c.Make(func(*testServer) {})
+}
+
+func Test_container_SingletonConcurrent(t *testing.T) {
+	var calls int32
+	c := newContainer()
+	c.Singleton(func() *testDatabase {
+		atomic.AddInt32(&calls, 1)
+		return &testDatabase{}
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Make(func(*testDatabase) {})
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("singleton resolver called %d times, want 1", calls)
+	}
+}
+
+func Test_container_Scoped(t *testing.T) {
+	c := newContainer()
+	c.Scoped(func() *testClosable { return &testClosable{} })
+
+	first, second := c.NewScope(), c.NewScope()
+	a1, a2 := di.Get[*testClosable](first), di.Get[*testClosable](first)
+	b := di.Get[*testClosable](second)
+	if a1 != a2 {
+		t.Errorf("scoped instances within a scope differ")
+	}
+	if a1 == b {
+		t.Errorf("scoped instances of different scopes are equal")
+	}
+
+	if err := first.Close(); err != nil || !a1.closed || b.closed {
+		t.Errorf("scope.Close() error = %v, closed = %v, %v", err, a1.closed, b.closed)
+	}
+
+	defer func() {
+		if err, ok := recover().(error); !ok || !strings.Contains(err.Error(), "outside of a scope") {
+			t.Errorf("container.Make() panic = %v, want outside of a scope", err)
+		}
+	}()
+	c.Make(func(*testClosable) {})
+}
+
+func Test_container_bindingInfos(t *testing.T) {
+	c := newContainer()
+	c.setProvider("test")
+	c.Singleton(func(db *testDatabase, server di.Optional[*testServer]) *testRepository { return &testRepository{db} })
+	c.Singleton(func() *testDatabase { return &testDatabase{} })
+	c.Transient(func() *testClosable { return &testClosable{} })
+
+	var got []string
+	for _, info := range c.bindingInfos() {
+		dependencies := make([]string, 0, len(info.dependencies))
+		for _, dependency := range info.dependencies {
+			dependencies = append(dependencies, dependency.String())
+		}
+		got = append(got, strings.Join([]string{
+			info.key.String(), info.lifetime.String(), info.provider, strings.Join(dependencies, ","),
+		}, " "))
+	}
+	want := []string{
+		"*application.testDatabase singleton test ",
+		"*application.testRepository singleton test *application.testDatabase,*application.testServer (optional, missing)",
+		"*application.testClosable transient test ",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("container.bindingInfos() = %q, want %q", got, want)
+	}
+}
+
+func Test_container_Inject(t *testing.T) {
+	type handler struct {
+		Repo      *testRepository          `inject:""`
+		Analytics *testDatabase            `inject:"analytics"`
+		Server    di.Optional[*testServer] `inject:""`
+		Skipped   *testDatabase
+	}
+
+	c := newContainer()
+	c.Singleton(func() *testDatabase { return &testDatabase{name: "default"} })
+	c.SingletonNamed("analytics", func() *testDatabase { return &testDatabase{name: "analytics"} })
+	c.Singleton(func(db *testDatabase) *testRepository { return &testRepository{db} })
+
+	got := di.New[*handler](c)
+	if got.Repo == nil || got.Repo.db.name != "default" {
+		t.Errorf("injected Repo = %v, want default repository", got.Repo)
+	}
+	if got.Analytics == nil || got.Analytics.name != "analytics" {
+		t.Errorf("injected Analytics = %v, want analytics database", got.Analytics)
+	}
+	if got.Server.Ok() || got.Skipped != nil {
+		t.Errorf("injected Server = %v, Skipped = %v, want empty", got.Server, got.Skipped)
+	}
+
+	defer func() {
+		err, ok := recover().(*MissingDependencyError)
+		if !ok || !reflect.DeepEqual(err.Path, []string{"application.missingHandler.Server"}) {
+			t.Errorf("container.Inject() panic = %v, want MissingDependencyError", err)
+		}
+	}()
+	c.Inject(&missingHandler{})
+}
+
+type missingHandler struct {
+	Server *testServer `inject:""`
+}
@@ -2,28 +2,73 @@ package application
 
 import (
 	"context"
+	"fmt"
 	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/errors"
+	"github.com/N-Vokhmyanin/go-framework/logger"
+	"go.uber.org/zap"
 	"reflect"
+	"sort"
 	"sync"
 )
 
+// defaultAsyncWorkers limits async listeners running at the same time.
+const defaultAsyncWorkers = 16
+
 var ctxType = reflect.TypeOf((*context.Context)(nil)).Elem()
 
 type dispatcher struct {
-	sync.Mutex
-	listeners map[string][]interface{}
+	sync.RWMutex
+	listeners map[reflect.Type][]*dispatcherListener
+	log       logger.Logger
+	workers   chan struct{}
+	pending   sync.WaitGroup
+}
+
+type dispatcherListener struct {
+	contracts.EventListener
+	dispatcher *dispatcher
+	eventType  reflect.Type
 }
 
-func NewDispatcher() contracts.Dispatcher {
-	return &dispatcher{
-		listeners: make(map[string][]interface{}),
+var _ contracts.Dispatcher = (*dispatcher)(nil)
+var _ contracts.CanStopContext = (*dispatcher)(nil)
+var _ contracts.Subscription = (*dispatcherListener)(nil)
+
+type DispatcherOption func(d *dispatcher)
+
+// DispatcherLoggerOption logs errors of async listeners and of listeners called by Fire.
+//
+//goland:noinspection GoUnusedExportedFunction
+func DispatcherLoggerOption(log logger.Logger) DispatcherOption {
+	return func(d *dispatcher) {
+		d.log = log
 	}
 }
 
-func (d *dispatcher) Listen(l interface{}) {
-	d.Lock()
-	defer d.Unlock()
+// DispatcherWorkersOption limits async listeners running at the same time, Dispatch waits for a free worker.
+//
+//goland:noinspection GoUnusedExportedFunction
+func DispatcherWorkersOption(workers int) DispatcherOption {
+	return func(d *dispatcher) {
+		if workers > 0 {
+			d.workers = make(chan struct{}, workers)
+		}
+	}
+}
 
+func NewDispatcher(opts ...DispatcherOption) contracts.Dispatcher {
+	d := &dispatcher{
+		listeners: make(map[reflect.Type][]*dispatcherListener),
+		workers:   make(chan struct{}, defaultAsyncWorkers),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+func (d *dispatcher) Listen(l interface{}) {
 	reflectType := reflect.TypeOf(l)
 	if reflectType.Kind() != reflect.Func {
 		panic("the listener must be a function")
@@ -40,26 +85,116 @@ func (d *dispatcher) Listen(l interface{}) {
 	if argumentType.Kind() != reflect.Struct {
 		panic("listener second argument must be a struct")
 	}
-	if _, ok := d.listeners[argumentType.String()]; !ok {
-		d.listeners[argumentType.String()] = make([]interface{}, 0)
-	}
-	d.listeners[argumentType.String()] = append(d.listeners[argumentType.String()], l)
+
+	listener := reflect.ValueOf(l)
+	d.Subscribe(argumentType, contracts.EventListener{
+		Handle: func(ctx context.Context, e interface{}) error {
+			listener.Call([]reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(e)})
+			return nil
+		},
+	})
 }
 
 func (d *dispatcher) Fire(ctx context.Context, e interface{}) {
-	reflectType := reflect.TypeOf(e)
-	if reflectType.Kind() != reflect.Struct {
+	if reflect.TypeOf(e).Kind() != reflect.Struct {
 		panic("dispatched event must be a struct")
 	}
-	listeners, ok := d.listeners[reflectType.String()]
-	if !ok || len(listeners) == 0 {
-		return
+	if err := d.Dispatch(ctx, e); err != nil {
+		d.logError(err, e)
 	}
-	callValues := []reflect.Value{
-		reflect.ValueOf(ctx),
-		reflect.ValueOf(e),
+}
+
+func (d *dispatcher) Subscribe(eventType reflect.Type, listener contracts.EventListener) contracts.Subscription {
+	if listener.Handle == nil {
+		panic("the listener handle must be a function")
 	}
+	d.Lock()
+	defer d.Unlock()
+
+	entry := &dispatcherListener{EventListener: listener, dispatcher: d, eventType: eventType}
+	listeners := append(d.listeners[eventType], entry)
+	// stable sort keeps the registration order of listeners with the same priority
+	sort.SliceStable(listeners, func(i, j int) bool {
+		return listeners[i].Priority > listeners[j].Priority
+	})
+	d.listeners[eventType] = listeners
+	return entry
+}
+
+// Dispatch calls synchronous listeners in order, a listener error or panic does not stop the others,
+// async listeners are started once synchronous ones are called, their errors are logged.
+func (d *dispatcher) Dispatch(ctx context.Context, e interface{}) error {
+	d.RLock()
+	listeners := append([]*dispatcherListener(nil), d.listeners[reflect.TypeOf(e)]...)
+	d.RUnlock()
+
+	errs := errors.NewMultiError()
+	var async []*dispatcherListener
 	for _, listener := range listeners {
-		reflect.ValueOf(listener).Call(callValues)
+		if listener.Async {
+			async = append(async, listener)
+			continue
+		}
+		errs.Append(listener.call(ctx, e))
+	}
+
+	// async listeners outlive the dispatching call, so its cancellation is not propagated
+	asyncCtx := context.WithoutCancel(ctx)
+	for _, listener := range async {
+		d.workers <- struct{}{}
+		d.pending.Add(1)
+		go func(listener *dispatcherListener) {
+			defer d.pending.Done()
+			defer func() { <-d.workers }()
+			if err := listener.call(asyncCtx, e); err != nil {
+				d.logError(err, e)
+			}
+		}(listener)
+	}
+	return errs.ErrorOrNil()
+}
+
+// StopService waits for async listeners in progress.
+func (d *dispatcher) StopService(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		d.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.WrapWith(ctx.Err(), "waiting for async event listeners")
+	}
+}
+
+func (d *dispatcher) logError(err error, e interface{}) {
+	if d.log != nil {
+		d.log.Errorw("event listener failed", "event", fmt.Sprintf("%T", e), zap.Error(err))
+	}
+}
+
+// call runs the listener, a panic is returned as an error.
+func (l *dispatcherListener) call(ctx context.Context, e interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.NewPanicError(r)
+		}
+	}()
+	return l.Handle(ctx, e)
+}
+
+func (l *dispatcherListener) Unsubscribe() {
+	d := l.dispatcher
+	d.Lock()
+	defer d.Unlock()
+
+	listeners := d.listeners[l.eventType]
+	for i, listener := range listeners {
+		if listener == l {
+			d.listeners[l.eventType] = append(listeners[:i:i], listeners[i+1:]...)
+			return
+		}
 	}
 }
@@ -2,27 +2,30 @@ package application
 
 import (
 	"context"
-	"github.com/N-Vokhmyanin/go-framework/contracts"
-	"reflect"
 	"testing"
 )
 
 func Test_newDispatcher(t *testing.T) {
 	tests := []struct {
-		name string
-		want contracts.Dispatcher
+		name    string
+		opts    []DispatcherOption
+		workers int
 	}{
 		{
-			name: "create new dispatcher",
-			want: &dispatcher{
-				listeners: make(map[string][]interface{}),
-			},
+			name:    "create new dispatcher",
+			workers: defaultAsyncWorkers,
+		},
+		{
+			name:    "create new dispatcher with workers",
+			opts:    []DispatcherOption{DispatcherWorkersOption(2)},
+			workers: 2,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := NewDispatcher(); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("NewDispatcher() = %v, want %v", got, tt.want)
+			got, ok := NewDispatcher(tt.opts...).(*dispatcher)
+			if !ok || len(got.listeners) != 0 || cap(got.workers) != tt.workers {
+				t.Errorf("NewDispatcher() = %v, want empty dispatcher with %d workers", got, tt.workers)
 			}
 		})
 	}
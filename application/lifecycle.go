@@ -0,0 +1,282 @@
+package application
+
+import (
+	"context"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/errors"
+	health "github.com/N-Vokhmyanin/go-framework/health/contracts"
+	"github.com/N-Vokhmyanin/go-framework/logger"
+	"github.com/N-Vokhmyanin/go-framework/utils/di"
+	"github.com/N-Vokhmyanin/go-framework/utils/rfl"
+	"go.uber.org/zap"
+	"reflect"
+	"time"
+)
+
+const (
+	phaseBoot  = "boot"
+	phaseInit  = "init"
+	phaseStart = "start"
+	phaseStop  = "stop"
+)
+
+type lifecycleTimeouts struct {
+	boot  time.Duration
+	init  time.Duration
+	start time.Duration
+	stop  time.Duration
+	drain time.Duration
+}
+
+// serviceCall returns the phase function of the instance, legacy interfaces are adapted.
+// Legacy services get no context, so they can not be cancelled: when the phase times out the call
+// keeps running in the background, e.g. a service may be stopped by rollback while it is still starting.
+func serviceCall(phase string, instance interface{}) func(ctx context.Context) error {
+	legacy := func(fn func()) func(ctx context.Context) error {
+		return func(context.Context) error {
+			fn()
+			return nil
+		}
+	}
+	switch phase {
+	case phaseBoot:
+		if s, ok := instance.(contracts.CanBootContext); ok {
+			return s.BootService
+		}
+		if s, ok := instance.(contracts.CanBoot); ok {
+			return legacy(s.BootService)
+		}
+	case phaseInit:
+		if s, ok := instance.(contracts.CanInitContext); ok {
+			return s.InitService
+		}
+		if s, ok := instance.(contracts.CanInit); ok {
+			return legacy(s.InitService)
+		}
+	case phaseStart:
+		if s, ok := instance.(contracts.CanStartContext); ok {
+			return s.StartService
+		}
+		if s, ok := instance.(contracts.CanStart); ok {
+			return legacy(s.StartService)
+		}
+	case phaseStop:
+		if s, ok := instance.(contracts.CanStopContext); ok {
+			return s.StopService
+		}
+		if s, ok := instance.(contracts.CanStop); ok {
+			return legacy(s.StopService)
+		}
+	}
+	return nil
+}
+
+// isLegacyService reports whether the instance implements the phase by the legacy interface without a context.
+func isLegacyService(phase string, instance interface{}) bool {
+	var ok bool
+	switch phase {
+	case phaseBoot:
+		_, ok = instance.(contracts.CanBootContext)
+	case phaseInit:
+		_, ok = instance.(contracts.CanInitContext)
+	case phaseStart:
+		_, ok = instance.(contracts.CanStartContext)
+	case phaseStop:
+		_, ok = instance.(contracts.CanStopContext)
+	}
+	return !ok
+}
+
+// callService runs the phase of the service, legacy services which time out are reported
+// as they keep running in the background.
+func (a *appInstance) callService(ctx context.Context, phase string, instance interface{}, call func(ctx context.Context) error) error {
+	err := callWithContext(ctx, call)
+	if err != nil && ctx.Err() != nil && isLegacyService(phase, instance) {
+		if log, ok := di.Maybe[logger.Logger](a).Get(); ok {
+			log.Warnw(
+				"legacy service timed out and keeps running in the background",
+				"phase", phase,
+				"service", rfl.FullTypeName(instance),
+			)
+		}
+	}
+	return err
+}
+
+// callWithContext runs fn until it returns or ctx is done, panics are returned as errors.
+func callWithContext(ctx context.Context, fn func(ctx context.Context) error) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- errors.NewPanicError(r)
+			}
+		}()
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func sameInstance(a, b interface{}) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+func containsInstance(instances []interface{}, instance interface{}) bool {
+	for _, item := range instances {
+		if sameInstance(item, instance) {
+			return true
+		}
+	}
+	return false
+}
+
+// runPhase calls the phase on every service in dependency order and stops on the first error.
+// Called services are passed to onCalled.
+func (a *appInstance) runPhase(
+	ctx context.Context,
+	phase string,
+	timeout time.Duration,
+	onCalled func(instance interface{}),
+) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	for _, instance := range a.Instances() {
+		if instance == a {
+			continue
+		}
+		call := serviceCall(phase, instance)
+		if call == nil {
+			continue
+		}
+		if err := a.callService(ctx, phase, instance, call); err != nil {
+			return errors.WrapWith(err, "%s %s", phase, rfl.FullTypeName(instance))
+		}
+		if onCalled != nil {
+			onCalled(instance)
+		}
+	}
+	return nil
+}
+
+// stopInstances stops services in reverse order, skips already stopped and not started ones and collects all errors.
+func (a *appInstance) stopInstances(ctx context.Context, instances []interface{}) error {
+	if a.timeouts.stop > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.timeouts.stop)
+		defer cancel()
+	}
+
+	errs := errors.NewMultiError()
+	for i := len(instances) - 1; i >= 0; i-- {
+		instance := instances[i]
+		if instance == a {
+			continue
+		}
+		call := serviceCall(phaseStop, instance)
+		if call == nil || !a.markStopped(instance) {
+			continue
+		}
+		if err := a.callService(ctx, phaseStop, instance, call); err != nil {
+			errs.Append(errors.WrapWith(err, "%s %s", phaseStop, rfl.FullTypeName(instance)))
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// markStopped marks the instance as stopped and reports whether it has to be stopped.
+// Once services are started, services with a start phase are stopped only if they are started.
+func (a *appInstance) markStopped(instance interface{}) bool {
+	a.lifecycleLock.Lock()
+	defer a.lifecycleLock.Unlock()
+
+	if containsInstance(a.stopped, instance) {
+		return false
+	}
+	if a.starting && serviceCall(phaseStart, instance) != nil && !containsInstance(a.started, instance) {
+		return false
+	}
+	a.stopped = append(a.stopped, instance)
+	return true
+}
+
+func (a *appInstance) markStarted(instance interface{}) {
+	a.lifecycleLock.Lock()
+	defer a.lifecycleLock.Unlock()
+
+	a.started = append(a.started, instance)
+}
+
+func (a *appInstance) BootServices(ctx context.Context) error {
+	return a.runPhase(ctx, phaseBoot, a.timeouts.boot, nil)
+}
+
+func (a *appInstance) InitServices(ctx context.Context) error {
+	return a.runPhase(ctx, phaseInit, a.timeouts.init, nil)
+}
+
+// StartServices starts services in dependency order,
+// on failure services which are already started are stopped in reverse order.
+func (a *appInstance) StartServices(ctx context.Context) error {
+	a.lifecycleLock.Lock()
+	a.starting = true
+	a.lifecycleLock.Unlock()
+
+	var started []interface{}
+	err := a.runPhase(ctx, phaseStart, a.timeouts.start, func(instance interface{}) {
+		a.markStarted(instance)
+		started = append(started, instance)
+	})
+	if err == nil {
+		return nil
+	}
+
+	errs := errors.NewMultiError().Append(err)
+	if stopErr := a.stopInstances(context.WithoutCancel(ctx), started); stopErr != nil {
+		errs.Append(errors.WrapWith(stopErr, "rollback"))
+	}
+	return errs.ErrorOrNil()
+}
+
+// StopServices stops services in reverse dependency order, every service is stopped once.
+func (a *appInstance) StopServices(ctx context.Context) error {
+	return a.stopInstances(ctx, a.Instances())
+}
+
+// Shutdown reports NOT_SERVING, waits the drain period so load balancers notice it
+// and then stops services, the stop timeout starts after the drain period.
+func (a *appInstance) Shutdown(ctx context.Context) error {
+	log := di.Get[logger.Logger](a).With(logger.WithComponent, "application")
+
+	log.Infow("shutdown: draining", "drain", a.timeouts.drain.String())
+	for _, instance := range a.Instances() {
+		if drainer, ok := instance.(health.Drainer); ok {
+			drainer.Drain()
+		}
+	}
+	select {
+	case <-time.After(a.timeouts.drain):
+	case <-ctx.Done():
+	}
+
+	log.Infow("shutdown: stopping services")
+	if err := a.StopServices(ctx); err != nil {
+		log.Errorw("shutdown: failed", zap.Error(err))
+		return err
+	}
+
+	log.Infow("shutdown: completed")
+	return nil
+}
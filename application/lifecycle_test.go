@@ -0,0 +1,130 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"github.com/N-Vokhmyanin/go-framework/logger"
+	"testing"
+	"time"
+)
+
+type testService struct {
+	name     string
+	startErr error
+	calls    *[]string
+}
+
+func (s *testService) StartService(context.Context) error {
+	*s.calls = append(*s.calls, "start "+s.name)
+	return s.startErr
+}
+
+func (s *testService) StopService(context.Context) error {
+	*s.calls = append(*s.calls, "stop "+s.name)
+	return nil
+}
+
+type testNotStartedService struct {
+	testService
+}
+
+type testLegacyService struct {
+	calls *[]string
+}
+
+func (s *testLegacyService) StartService() {
+	*s.calls = append(*s.calls, "start legacy")
+}
+
+func (s *testLegacyService) StopService() {
+	*s.calls = append(*s.calls, "stop legacy")
+}
+
+type testStopContextService struct {
+	err error
+}
+
+func (s *testStopContextService) StopService(ctx context.Context) error {
+	s.err = ctx.Err()
+	return nil
+}
+
+type testBlockingService struct{}
+
+func (testBlockingService) InitService() {
+	select {}
+}
+
+// testWarnLogger records warning messages.
+type testWarnLogger struct {
+	logger.Logger
+	warnings []string
+}
+
+func (l *testWarnLogger) Warnw(msg string, _ ...interface{}) {
+	l.warnings = append(l.warnings, msg)
+}
+
+func Test_appInstance_StartServicesRollback(t *testing.T) {
+	var calls []string
+	a := &appInstance{container: newContainer()}
+	a.Singleton(func() *testLegacyService { return &testLegacyService{calls: &calls} })
+	a.Singleton(func(*testLegacyService) *testDatabase { return &testDatabase{} })
+	a.Singleton(func(*testDatabase) *testService {
+		return &testService{name: "failed", startErr: errors.New("failed"), calls: &calls}
+	})
+	a.Singleton(func(*testService) *testNotStartedService {
+		return &testNotStartedService{testService{name: "not started", calls: &calls}}
+	})
+
+	if err := a.StartServices(context.Background()); err == nil {
+		t.Fatalf("appInstance.StartServices() error = nil, want error")
+	}
+	if err := a.StopServices(context.Background()); err != nil {
+		t.Fatalf("appInstance.StopServices() error = %v", err)
+	}
+
+	// the failed and not reached services are not started, so they are not stopped
+	want := []string{"start legacy", "start failed", "stop legacy"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("calls = %v, want %v", calls, want)
+		}
+	}
+}
+
+func Test_appInstance_InitServicesTimeout(t *testing.T) {
+	a := &appInstance{container: newContainer()}
+	a.timeouts.init = 10 * time.Millisecond
+	log := &testWarnLogger{Logger: logger.GetNopLogger()}
+	a.Singleton(func() logger.Logger { return log })
+	a.Singleton(func() *testBlockingService { return &testBlockingService{} })
+
+	err := a.InitServices(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("appInstance.InitServices() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	// the legacy service can not be cancelled, so it is reported as running in the background
+	if len(log.warnings) != 1 {
+		t.Errorf("warnings = %v, want one about the legacy service", log.warnings)
+	}
+}
+
+func Test_appInstance_ShutdownAfterDrain(t *testing.T) {
+	a := &appInstance{container: newContainer()}
+	a.timeouts.drain = 30 * time.Millisecond
+	a.timeouts.stop = 20 * time.Millisecond
+	a.Singleton(func() logger.Logger { return logger.GetNopLogger() })
+	service := &testStopContextService{}
+	a.Singleton(func() *testStopContextService { return service })
+
+	if err := a.Shutdown(context.Background()); err != nil {
+		t.Fatalf("appInstance.Shutdown() error = %v", err)
+	}
+	if service.err != nil {
+		t.Errorf("stop context error = %v, want nil", service.err)
+	}
+}
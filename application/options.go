@@ -6,9 +6,52 @@ import (
 
 type Option func(a *appInstance)
 
+// StrictContainerOption validates the whole container graph before services boot.
+//
+//goland:noinspection GoUnusedExportedFunction
+func StrictContainerOption() Option {
+	return func(a *appInstance) {
+		a.strict = true
+	}
+}
+
 //goland:noinspection GoUnusedExportedFunction
 func LoggerOption(p logger.Provider) Option {
 	return func(a *appInstance) {
 		a.loggerProvider = p
 	}
 }
+
+// ConfigValuesOption sets configuration values in code, they take precedence over flags and environment,
+// values of unknown names are ignored.
+//
+//goland:noinspection GoUnusedExportedFunction
+func ConfigValuesOption(values map[string]string) Option {
+	return func(a *appInstance) {
+		if a.configValues == nil {
+			a.configValues = map[string]string{}
+		}
+		for name, value := range values {
+			a.configValues[name] = value
+		}
+	}
+}
+
+// OverrideOption binds singleton resolvers after providers boot, replacing the bindings of providers.
+//
+//goland:noinspection GoUnusedExportedFunction
+func OverrideOption(resolvers ...interface{}) Option {
+	return func(a *appInstance) {
+		a.overrides = append(a.overrides, resolvers...)
+	}
+}
+
+// ConfigFilesOption adds YAML, JSON or .env config files, they are read after the files
+// listed in the CONFIG environment variable and before the ones passed with -config flags.
+//
+//goland:noinspection GoUnusedExportedFunction
+func ConfigFilesOption(paths ...string) Option {
+	return func(a *appInstance) {
+		a.configFiles = append(a.configFiles, paths...)
+	}
+}
@@ -1,20 +1,28 @@
 package application
 
 import (
+	"github.com/N-Vokhmyanin/go-framework/application/config"
 	"github.com/N-Vokhmyanin/go-framework/contracts"
 	"github.com/N-Vokhmyanin/go-framework/logger"
 	"os"
 	"strings"
+	"time"
 )
 
+// appEnvFlagName is the name of the application environment, it also picks config profile files.
+const appEnvFlagName = "APP_ENV"
+
 type appProvider struct {
 	app *appInstance
+
+	configWatchInterval time.Duration
+	asyncEventWorkers   int
 }
 
 var _ contracts.Provider = (*appProvider)(nil)
 
 func newAppProvider(app *appInstance) contracts.Provider {
-	return &appProvider{app}
+	return &appProvider{app: app}
 }
 
 func (p *appProvider) Config(c contracts.ConfigSet) {
@@ -24,7 +32,17 @@ func (p *appProvider) Config(c contracts.ConfigSet) {
 		defaultAppName = pathParts[len(pathParts)-1]
 	}
 	c.StringVar(&p.app.name, "APP_NAME", defaultAppName, "application name")
-	c.StringVar(&p.app.env, "APP_ENV", "development", "app environment")
+	c.StringVar(&p.app.env, appEnvFlagName, contracts.EnvDevelopment, "app environment, picks config profile files like config.production.yaml")
+
+	c.DurationVar(&p.app.timeouts.boot, "APP_BOOT_TIMEOUT", 30*time.Second, "services boot timeout")
+	c.DurationVar(&p.app.timeouts.init, "APP_INIT_TIMEOUT", 30*time.Second, "services init timeout")
+	c.DurationVar(&p.app.timeouts.start, "APP_START_TIMEOUT", time.Minute, "services start timeout")
+	c.DurationVar(&p.app.timeouts.stop, "APP_SHUTDOWN_TIMEOUT", time.Minute, "graceful shutdown timeout, counted after the drain period")
+	c.DurationVar(&p.app.timeouts.drain, "APP_SHUTDOWN_DRAIN", 0, "readiness drain period before services are stopped")
+
+	c.DurationVar(&p.configWatchInterval, "CONFIG_WATCH_INTERVAL", 5*time.Second, "config files polling interval, 0 disables polling")
+	c.IntVar(&p.asyncEventWorkers, "EVENTS_ASYNC_WORKERS", defaultAsyncWorkers, "async event listeners running at the same time")
+	config.Rules(c, "EVENTS_ASYNC_WORKERS", config.Min(1))
 }
 
 func (p *appProvider) Boot(a contracts.Application) {
@@ -34,13 +52,22 @@ func (p *appProvider) Boot(a contracts.Application) {
 	a.Singleton(func() contracts.Config {
 		return p.app.config
 	})
-	a.Singleton(func() contracts.Dispatcher {
-		return NewDispatcher()
+	a.Singleton(func(log logger.Logger) contracts.Dispatcher {
+		return NewDispatcher(
+			DispatcherLoggerOption(log.With(logger.WithComponent, "events")),
+			DispatcherWorkersOption(p.asyncEventWorkers),
+		)
 	})
+	if cfg, ok := p.app.config.(*defaultConfig); ok {
+		a.Singleton(func(log logger.Logger) *configWatcher {
+			return newConfigWatcher(cfg, p.configWatchInterval, log)
+		})
+	}
 }
 
 func (p *appProvider) Register(a contracts.Application) {
 	a.Make(func(cfg contracts.Config, log logger.Logger) {
 		a.Command(NewAppCommands(a, cfg, log)...)
+		a.Command(newContainerCommand(p.app.container))
 	})
 }
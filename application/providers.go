@@ -0,0 +1,99 @@
+package application
+
+import (
+	"fmt"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/errors"
+	"github.com/N-Vokhmyanin/go-framework/utils/rfl"
+	"strings"
+)
+
+// sortProviders orders providers so that each one goes after the providers it depends on,
+// ties are broken by the order they were provided.
+func sortProviders(providers []contracts.Provider) ([]contracts.Provider, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	byName := make(map[string][]int, len(providers))
+	for i, provider := range providers {
+		name := providerName(provider)
+		byName[name] = append(byName[name], i)
+	}
+
+	errs := errors.NewMultiError()
+	dependencies := make([][]int, len(providers))
+	for i, provider := range providers {
+		if p, ok := provider.(contracts.ProviderWithDependencies); ok {
+			for _, name := range p.DependsOn() {
+				if len(byName[name]) == 0 {
+					errs.Append(fmt.Errorf("provider %s requires provider %s which is not provided", providerName(provider), name))
+				}
+				dependencies[i] = append(dependencies[i], byName[name]...)
+			}
+		}
+		if p, ok := provider.(contracts.ProviderWithOptionalDependencies); ok {
+			for _, name := range p.After() {
+				dependencies[i] = append(dependencies[i], byName[name]...)
+			}
+		}
+	}
+	if errs.HasErrors() {
+		return nil, errs.ErrorOrNil()
+	}
+
+	state := make([]int, len(providers))
+	sorted := make([]contracts.Provider, 0, len(providers))
+	var path []int
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			var cycle []string
+			for j := len(path) - 1; j >= 0; j-- {
+				cycle = append([]string{providerName(providers[path[j]])}, cycle...)
+				if path[j] == i {
+					break
+				}
+			}
+			cycle = append(cycle, providerName(providers[i]))
+			return fmt.Errorf("provider dependency cycle detected: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[i] = visiting
+		path = append(path, i)
+		for _, dependency := range dependencies[i] {
+			if dependency == i {
+				continue
+			}
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+
+		sorted = append(sorted, providers[i])
+		return nil
+	}
+
+	for i := range providers {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// providerName returns the declared provider name or the provider type name.
+func providerName(provider contracts.Provider) string {
+	if p, ok := provider.(contracts.ProviderWithName); ok {
+		return p.ProviderName()
+	}
+	return rfl.FullTypeName(provider)
+}
@@ -0,0 +1,98 @@
+package application
+
+import (
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testProvider struct {
+	name      string
+	dependsOn []string
+	after     []string
+}
+
+func (p *testProvider) ProviderName() string           { return p.name }
+func (p *testProvider) Config(contracts.ConfigSet)     {}
+func (p *testProvider) Boot(contracts.Application)     {}
+func (p *testProvider) Register(contracts.Application) {}
+func (p *testProvider) DependsOn() []string            { return p.dependsOn }
+func (p *testProvider) After() []string                { return p.after }
+
+type testUnnamedProvider struct{}
+
+func (p *testUnnamedProvider) Config(contracts.ConfigSet)     {}
+func (p *testUnnamedProvider) Boot(contracts.Application)     {}
+func (p *testUnnamedProvider) Register(contracts.Application) {}
+
+func Test_sortProviders(t *testing.T) {
+	tests := []struct {
+		name      string
+		providers []contracts.Provider
+		want      []string
+		wantErr   string
+	}{
+		{
+			name: "dependencies go first",
+			providers: []contracts.Provider{
+				&testProvider{name: "a", dependsOn: []string{"b"}},
+				&testProvider{name: "c"},
+				&testProvider{name: "b"},
+			},
+			want: []string{"b", "a", "c"},
+		},
+		{
+			name: "optional dependencies go first when provided",
+			providers: []contracts.Provider{
+				&testProvider{name: "a", after: []string{"b", "c"}},
+				&testProvider{name: "c"},
+			},
+			want: []string{"c", "a"},
+		},
+		{
+			name: "unnamed providers are named by type",
+			providers: []contracts.Provider{
+				&testProvider{name: "a", dependsOn: []string{"github.com/N-Vokhmyanin/go-framework/application.testUnnamedProvider"}},
+				&testUnnamedProvider{},
+			},
+			want: []string{"github.com/N-Vokhmyanin/go-framework/application.testUnnamedProvider", "a"},
+		},
+		{
+			name: "missing dependency",
+			providers: []contracts.Provider{
+				&testProvider{name: "a", dependsOn: []string{"b"}},
+			},
+			wantErr: "provider a requires provider b which is not provided",
+		},
+		{
+			name: "cycle",
+			providers: []contracts.Provider{
+				&testProvider{name: "a", dependsOn: []string{"b"}},
+				&testProvider{name: "b", dependsOn: []string{"a"}},
+			},
+			wantErr: "provider dependency cycle detected: a -> b -> a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sortProviders(tt.providers)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("sortProviders() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sortProviders() error = %v", err)
+			}
+			var names []string
+			for _, provider := range got {
+				names = append(names, providerName(provider))
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("sortProviders() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
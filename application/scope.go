@@ -0,0 +1,142 @@
+package application
+
+import (
+	"fmt"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/errors"
+	"sync"
+)
+
+type scope struct {
+	sync.Mutex
+	root      *container
+	entries   map[*binding]*scopedEntry
+	instances []interface{} // scoped instances in creation order
+	closed    bool
+}
+
+type scopedEntry struct {
+	sync.Mutex // guards scoped instance construction
+	instance   interface{}
+	resolved   bool
+}
+
+var _ contracts.Scope = (*scope)(nil)
+
+func newScope(root *container) *scope {
+	return &scope{
+		root:    root,
+		entries: map[*binding]*scopedEntry{},
+	}
+}
+
+func (s *scope) entry(concrete *binding) *scopedEntry {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.closed {
+		panic(fmt.Errorf("scoped binding %s resolved from a closed scope", concrete.key))
+	}
+	entry, ok := s.entries[concrete]
+	if !ok {
+		entry = &scopedEntry{}
+		s.entries[concrete] = entry
+	}
+	return entry
+}
+
+func (s *scope) resolve(r *resolution, concrete *binding) interface{} {
+	entry := s.entry(concrete)
+
+	entry.Lock()
+	defer entry.Unlock()
+
+	if entry.resolved {
+		return entry.instance
+	}
+
+	entry.instance = s.root.invoke(r, concrete)
+	entry.resolved = true
+
+	s.Lock()
+	s.instances = append(s.instances, entry.instance)
+	s.Unlock()
+
+	return entry.instance
+}
+
+// Close disposes scoped instances in reverse creation order,
+// instances implementing Close() error or Close() are closed.
+func (s *scope) Close() error {
+	s.Lock()
+	if s.closed {
+		s.Unlock()
+		return nil
+	}
+	s.closed = true
+	instances := s.instances
+	s.instances = nil
+	s.Unlock()
+
+	errs := errors.NewMultiError()
+	for i := len(instances) - 1; i >= 0; i-- {
+		switch closer := instances[i].(type) {
+		case interface{ Close() error }:
+			if err := closer.Close(); err != nil {
+				errs.Append(err)
+			}
+		case interface{ Close() }:
+			closer.Close()
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+func (s *scope) Instances() []interface{} {
+	return s.root.Instances()
+}
+
+func (s *scope) Singleton(resolver interface{}) {
+	s.root.Singleton(resolver)
+}
+
+func (s *scope) Transient(resolver interface{}) {
+	s.root.Transient(resolver)
+}
+
+func (s *scope) Scoped(resolver interface{}) {
+	s.root.Scoped(resolver)
+}
+
+func (s *scope) SingletonNamed(name string, resolver interface{}) {
+	s.root.SingletonNamed(name, resolver)
+}
+
+func (s *scope) TransientNamed(name string, resolver interface{}) {
+	s.root.TransientNamed(name, resolver)
+}
+
+func (s *scope) Tag(tag string, resolver interface{}) {
+	s.root.Tag(tag, resolver)
+}
+
+func (s *scope) Make(receiver interface{}) []interface{} {
+	return s.root.make(&resolution{scope: s}, receiver)
+}
+
+func (s *scope) MakeNamed(name string, receiver interface{}) {
+	s.root.makeNamed(&resolution{scope: s}, name, receiver)
+}
+
+func (s *scope) Inject(target interface{}) {
+	s.root.inject(&resolution{scope: s}, target)
+}
+
+func (s *scope) MakeTagged(tag string) []interface{} {
+	return s.root.makeTagged(&resolution{scope: s}, tag)
+}
+
+// NewScope returns a sibling scope, scopes are not nested.
+func (s *scope) NewScope() contracts.Scope {
+	return newScope(s.root)
+}
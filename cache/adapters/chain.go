@@ -0,0 +1,163 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	goCache "github.com/eko/gocache/v2/cache"
+	"github.com/eko/gocache/v2/store"
+	"sync"
+	"time"
+)
+
+// twoLevelCache reads through a local L1 cache to a shared L2 cache. Changes are written to L2,
+// evicted from L1 and published, so other instances evict their L1 copies too.
+// L1 is filled on reads only, so it holds values as L2 returns them.
+type twoLevelCache struct {
+	l1          goCache.CacheInterface
+	l2          goCache.CacheInterface
+	invalidator Invalidator
+
+	mu    sync.Mutex
+	fills map[string]*l1Fill
+}
+
+// l1Fill tracks reads of a key from L2 in flight, evictions bump its generation,
+// so a value read before an eviction is not put into L1 after it.
+type l1Fill struct {
+	refs       int
+	generation uint64
+}
+
+// ttlGetter is implemented by L2 caches which report the remaining ttl of values.
+type ttlGetter interface {
+	GetWithTTL(ctx context.Context, key interface{}) (interface{}, time.Duration, error)
+}
+
+var _ goCache.CacheInterface = (*twoLevelCache)(nil)
+
+func NewTwoLevelCache(l1, l2 goCache.CacheInterface, invalidator Invalidator) goCache.CacheInterface {
+	c := &twoLevelCache{
+		l1:          l1,
+		l2:          l2,
+		invalidator: invalidator,
+		fills:       make(map[string]*l1Fill),
+	}
+	invalidator.Subscribe(c.evict)
+	return c
+}
+
+// Get reads L2 only while the invalidator is not running, e.g. in commands which do not start services,
+// as L1 copies would miss invalidations of other instances.
+func (c *twoLevelCache) Get(ctx context.Context, key interface{}) (interface{}, error) {
+	if !c.invalidator.Running() {
+		return c.l2.Get(ctx, key)
+	}
+	if value, err := c.l1.Get(ctx, key); err == nil && value != nil {
+		return value, nil
+	}
+	k := fmt.Sprint(key)
+	fill, generation := c.beginFill(k)
+	value, ttl, err := c.getL2(ctx, key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.endFill(k, fill)
+	if err != nil || value == nil || fill.generation != generation {
+		return value, err
+	}
+	// L1 entries expire with the L1 ttl, which bounds staleness when an invalidation is missed,
+	// or earlier when the value expires in L2
+	var options *store.Options
+	if ttl > 0 {
+		options = &store.Options{Expiration: ttl}
+	}
+	_ = c.l1.Set(ctx, key, value, options)
+	return value, nil
+}
+
+func (c *twoLevelCache) getL2(ctx context.Context, key interface{}) (interface{}, time.Duration, error) {
+	if l2, ok := c.l2.(ttlGetter); ok {
+		return l2.GetWithTTL(ctx, key)
+	}
+	value, err := c.l2.Get(ctx, key)
+	return value, 0, err
+}
+
+func (c *twoLevelCache) beginFill(key string) (*l1Fill, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fill, ok := c.fills[key]
+	if !ok {
+		fill = &l1Fill{}
+		c.fills[key] = fill
+	}
+	fill.refs++
+	return fill, fill.generation
+}
+
+// endFill must be called with c.mu held.
+func (c *twoLevelCache) endFill(key string, fill *l1Fill) {
+	fill.refs--
+	if fill.refs == 0 {
+		delete(c.fills, key)
+	}
+}
+
+func (c *twoLevelCache) Set(ctx context.Context, key, object interface{}, options *store.Options) error {
+	if err := c.l2.Set(ctx, key, object, options); err != nil {
+		return err
+	}
+	return c.publish(ctx, Invalidation{Keys: []string{fmt.Sprint(key)}})
+}
+
+func (c *twoLevelCache) Delete(ctx context.Context, key interface{}) error {
+	if err := c.l2.Delete(ctx, key); err != nil {
+		return err
+	}
+	return c.publish(ctx, Invalidation{Keys: []string{fmt.Sprint(key)}})
+}
+
+func (c *twoLevelCache) Invalidate(ctx context.Context, options store.InvalidateOptions) error {
+	if err := c.l2.Invalidate(ctx, options); err != nil {
+		return err
+	}
+	return c.publish(ctx, Invalidation{Tags: options.Tags})
+}
+
+func (c *twoLevelCache) Clear(ctx context.Context) error {
+	if err := c.l2.Clear(ctx); err != nil {
+		return err
+	}
+	return c.publish(ctx, Invalidation{Clear: true})
+}
+
+func (c *twoLevelCache) GetType() string {
+	return "two-level"
+}
+
+// publish evicts local copies and publishes the invalidation to other instances.
+func (c *twoLevelCache) publish(ctx context.Context, inv Invalidation) error {
+	c.evict(ctx, inv)
+	return c.invalidator.Publish(ctx, inv)
+}
+
+// evict removes local copies, L1 does not know tags of values read from L2, so tags clear it.
+func (c *twoLevelCache) evict(ctx context.Context, inv Invalidation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if inv.Clear || len(inv.Tags) > 0 {
+		for _, fill := range c.fills {
+			fill.generation++
+		}
+		_ = c.l1.Clear(ctx)
+		return
+	}
+	for _, key := range inv.Keys {
+		if fill, ok := c.fills[key]; ok {
+			fill.generation++
+		}
+		_ = c.l1.Delete(ctx, key)
+	}
+}
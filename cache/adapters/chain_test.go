@@ -0,0 +1,146 @@
+package adapters
+
+import (
+	"context"
+	goCache "github.com/eko/gocache/v2/cache"
+	"github.com/eko/gocache/v2/store"
+	goMemCache "github.com/patrickmn/go-cache"
+	"testing"
+	"time"
+)
+
+// testBus delivers invalidations to subscribers of other instances, like redis pub/sub.
+type testBus struct {
+	subscribers map[*testInvalidator]func(ctx context.Context, inv Invalidation)
+}
+
+type testInvalidator struct {
+	bus     *testBus
+	stopped bool
+}
+
+func (i *testInvalidator) Running() bool {
+	return !i.stopped
+}
+
+func (i *testInvalidator) Publish(ctx context.Context, inv Invalidation) error {
+	for origin, fn := range i.bus.subscribers {
+		if origin != i {
+			fn(ctx, inv)
+		}
+	}
+	return nil
+}
+
+func (i *testInvalidator) Subscribe(fn func(ctx context.Context, inv Invalidation)) {
+	i.bus.subscribers[i] = fn
+}
+
+func Test_twoLevelCache(t *testing.T) {
+	ctx := context.Background()
+	bus := &testBus{subscribers: make(map[*testInvalidator]func(ctx context.Context, inv Invalidation))}
+	l2 := NewMemoryCache()
+	first := NewTwoLevelCache(NewLocalCache(10, 0, PolicyLRU), l2, &testInvalidator{bus: bus})
+	second := NewTwoLevelCache(NewLocalCache(10, 0, PolicyLRU), l2, &testInvalidator{bus: bus})
+
+	_ = first.Set(ctx, "key", "v1", nil)
+	if got, _ := second.Get(ctx, "key"); got != "v1" {
+		t.Errorf("Get() = %v, want v1 from L2", got)
+	}
+
+	_ = first.Set(ctx, "key", "v2", nil)
+	if got, _ := second.Get(ctx, "key"); got != "v2" {
+		t.Errorf("Get() after Set() on another instance = %v, want v2", got)
+	}
+
+	_ = first.Delete(ctx, "key")
+	if got, _ := second.Get(ctx, "key"); got != nil {
+		t.Errorf("Get() after Delete() on another instance = %v, want nil", got)
+	}
+
+	_ = second.Set(ctx, "key", "v3", nil)
+	_, _ = first.Get(ctx, "key")
+	_ = l2.Set(ctx, "key", "v4", nil) // changed bypassing the two-level cache
+	if got, _ := first.Get(ctx, "key"); got != "v3" {
+		t.Errorf("Get() = %v, want v3 from L1", got)
+	}
+	_ = second.Clear(ctx)
+	_ = l2.Set(ctx, "key", "v5", nil)
+	if got, _ := first.Get(ctx, "key"); got != "v5" {
+		t.Errorf("Get() after Clear() on another instance = %v, want v5", got)
+	}
+}
+
+// testHookCache runs onGet after reading a value, like an invalidation arriving during the read.
+type testHookCache struct {
+	goCache.CacheInterface
+	onGet func()
+}
+
+func (c *testHookCache) Get(ctx context.Context, key interface{}) (interface{}, error) {
+	value, err := c.CacheInterface.Get(ctx, key)
+	if c.onGet != nil {
+		c.onGet()
+		c.onGet = nil
+	}
+	return value, err
+}
+
+func Test_twoLevelCache_InvalidatorNotRunning(t *testing.T) {
+	ctx := context.Background()
+	bus := &testBus{subscribers: make(map[*testInvalidator]func(ctx context.Context, inv Invalidation))}
+	l2 := NewMemoryCache()
+	invalidator := &testInvalidator{bus: bus, stopped: true}
+	c := NewTwoLevelCache(NewLocalCache(10, 0, PolicyLRU), l2, invalidator)
+
+	_ = l2.Set(ctx, "key", "v1", nil)
+	if got, _ := c.Get(ctx, "key"); got != "v1" {
+		t.Fatalf("Get() = %v, want v1", got)
+	}
+	_ = l2.Set(ctx, "key", "v2", nil) // changed by an instance whose invalidations are not received
+	if got, _ := c.Get(ctx, "key"); got != "v2" {
+		t.Errorf("Get() while the invalidator is not running = %v, want v2 from L2", got)
+	}
+
+	invalidator.stopped = false
+	_, _ = c.Get(ctx, "key")
+	_ = l2.Set(ctx, "key", "v3", nil)
+	if got, _ := c.Get(ctx, "key"); got != "v2" {
+		t.Errorf("Get() while the invalidator is running = %v, want v2 from L1", got)
+	}
+}
+
+func Test_twoLevelCache_Fill(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("expires with L2 ttl", func(t *testing.T) {
+		bus := &testBus{subscribers: make(map[*testInvalidator]func(ctx context.Context, inv Invalidation))}
+		l2 := goCache.New(store.NewGoCache(goMemCache.New(time.Minute, time.Minute), nil))
+		c := NewTwoLevelCache(NewLocalCache(10, time.Hour, PolicyLRU), l2, &testInvalidator{bus: bus})
+
+		_ = c.Set(ctx, "key", "v1", &store.Options{Expiration: 50 * time.Millisecond})
+		if got, _ := c.Get(ctx, "key"); got != "v1" {
+			t.Fatalf("Get() = %v, want v1", got)
+		}
+		time.Sleep(100 * time.Millisecond)
+		if got, _ := c.Get(ctx, "key"); got != nil {
+			t.Errorf("Get() after L2 expiration = %v, want nil", got)
+		}
+	})
+
+	t.Run("invalidation during L2 read", func(t *testing.T) {
+		bus := &testBus{subscribers: make(map[*testInvalidator]func(ctx context.Context, inv Invalidation))}
+		l2 := &testHookCache{CacheInterface: NewMemoryCache()}
+		first := NewTwoLevelCache(NewLocalCache(10, 0, PolicyLRU), l2, &testInvalidator{bus: bus})
+		second := NewTwoLevelCache(NewLocalCache(10, 0, PolicyLRU), l2, &testInvalidator{bus: bus})
+
+		_ = second.Set(ctx, "key", "v1", nil)
+		l2.onGet = func() { _ = second.Set(ctx, "key", "v2", nil) }
+		if got, _ := first.Get(ctx, "key"); got != "v1" {
+			t.Fatalf("Get() = %v, want v1", got)
+		}
+		if got, _ := first.Get(ctx, "key"); got != "v2" {
+			t.Errorf("Get() after invalidation during the read = %v, want v2", got)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/logger"
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Invalidation is an eviction of local copies, by keys, by tags or all of them.
+type Invalidation struct {
+	Keys  []string `json:"keys,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+	Clear bool     `json:"clear,omitempty"`
+}
+
+// Invalidator delivers invalidations to other instances.
+type Invalidator interface {
+	Publish(ctx context.Context, inv Invalidation) error
+	// Subscribe calls fn with invalidations published by other instances.
+	Subscribe(fn func(ctx context.Context, inv Invalidation))
+	// Running reports whether invalidations of other instances are received.
+	Running() bool
+}
+
+// invalidationMessage is an invalidation with the instance which published it.
+type invalidationMessage struct {
+	Invalidation
+	Origin string `json:"origin"`
+}
+
+// redisInvalidator delivers invalidations through a redis pub/sub channel.
+type redisInvalidator struct {
+	mu          sync.RWMutex
+	client      redis.UniversalClient
+	channel     string
+	origin      string
+	log         logger.Logger
+	subscribers []func(ctx context.Context, inv Invalidation)
+	pubsub      *redis.PubSub
+	done        chan struct{}
+	running     atomic.Bool
+}
+
+var _ Invalidator = (*redisInvalidator)(nil)
+var _ contracts.CanStartContext = (*redisInvalidator)(nil)
+var _ contracts.CanStopContext = (*redisInvalidator)(nil)
+
+//goland:noinspection GoExportedFuncWithUnexportedType
+func NewRedisInvalidator(client redis.UniversalClient, channel string, log logger.Logger) *redisInvalidator {
+	return &redisInvalidator{
+		client:  client,
+		channel: channel,
+		origin:  strconv.FormatUint(rand.Uint64(), 36),
+		log:     log.With(logger.WithComponent, "cache.invalidator"),
+	}
+}
+
+func (i *redisInvalidator) Publish(ctx context.Context, inv Invalidation) error {
+	body, err := json.Marshal(invalidationMessage{Invalidation: inv, Origin: i.origin})
+	if err != nil {
+		return err
+	}
+	return i.client.Publish(ctx, i.channel, body).Err()
+}
+
+func (i *redisInvalidator) Subscribe(fn func(ctx context.Context, inv Invalidation)) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.subscribers = append(i.subscribers, fn)
+}
+
+func (i *redisInvalidator) Running() bool {
+	return i.running.Load()
+}
+
+func (i *redisInvalidator) StartService(ctx context.Context) error {
+	i.pubsub = i.client.Subscribe(ctx, i.channel)
+	if _, err := i.pubsub.Receive(ctx); err != nil {
+		_ = i.pubsub.Close()
+		return err
+	}
+	i.done = make(chan struct{})
+	go i.receive(i.pubsub.Channel())
+	i.running.Store(true)
+
+	// copies kept before a restart may have missed invalidations
+	i.notify(Invalidation{Clear: true})
+	return nil
+}
+
+func (i *redisInvalidator) StopService(ctx context.Context) error {
+	if i.pubsub == nil {
+		return nil
+	}
+	i.running.Store(false)
+	if err := i.pubsub.Close(); err != nil {
+		return err
+	}
+	select {
+	case <-i.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (i *redisInvalidator) receive(messages <-chan *redis.Message) {
+	defer close(i.done)
+	for message := range messages {
+		var msg invalidationMessage
+		if err := json.Unmarshal([]byte(message.Payload), &msg); err != nil {
+			i.log.Warnw("invalid cache invalidation", zap.Error(err))
+			continue
+		}
+		if msg.Origin == i.origin {
+			continue
+		}
+
+		i.notify(msg.Invalidation)
+	}
+}
+
+func (i *redisInvalidator) notify(inv Invalidation) {
+	i.mu.RLock()
+	subscribers := i.subscribers
+	i.mu.RUnlock()
+	for _, fn := range subscribers {
+		fn(context.Background(), inv)
+	}
+}
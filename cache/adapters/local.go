@@ -0,0 +1,246 @@
+package adapters
+
+import (
+	"container/heap"
+	"container/list"
+	"context"
+	"fmt"
+	goCache "github.com/eko/gocache/v2/cache"
+	"github.com/eko/gocache/v2/store"
+	"sync"
+	"time"
+)
+
+const (
+	PolicyLRU = "lru"
+	PolicyLFU = "lfu"
+)
+
+// localCache is a bounded in-process cache, when it is full an entry is evicted by the policy:
+// the least recently used one for LRU, the least frequently used one for LFU.
+type localCache struct {
+	sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*localEntry
+	tags    map[string]map[string]struct{}
+	policy  evictionPolicy
+}
+
+var _ goCache.CacheInterface = (*localCache)(nil)
+
+type localEntry struct {
+	key     string
+	value   interface{}
+	tags    []string
+	expires time.Time
+	hits    uint64
+	used    uint64
+	element *list.Element // LRU position
+	index   int           // LFU heap index
+}
+
+// NewLocalCache creates a cache of at most size entries, each kept at most for ttl, zero ttl keeps entries until evicted.
+func NewLocalCache(size int, ttl time.Duration, policy string) goCache.CacheInterface {
+	c := &localCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*localEntry),
+		tags:    make(map[string]map[string]struct{}),
+	}
+	if policy == PolicyLFU {
+		c.policy = &lfuPolicy{}
+	} else {
+		c.policy = &lruPolicy{list: list.New()}
+	}
+	return c
+}
+
+func (c *localCache) Get(_ context.Context, key interface{}) (interface{}, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	e, ok := c.entries[fmt.Sprint(key)]
+	if !ok {
+		return nil, nil
+	}
+	if !e.expires.IsZero() && !time.Now().Before(e.expires) {
+		c.remove(e)
+		return nil, nil
+	}
+	c.policy.touch(e)
+	return e.value, nil
+}
+
+func (c *localCache) Set(_ context.Context, key, object interface{}, options *store.Options) error {
+	c.Lock()
+	defer c.Unlock()
+
+	k := fmt.Sprint(key)
+	if e, ok := c.entries[k]; ok {
+		c.remove(e)
+	}
+	if c.size <= 0 {
+		return nil
+	}
+	for len(c.entries) >= c.size {
+		c.remove(c.policy.victim())
+	}
+
+	ttl := c.ttl
+	e := &localEntry{key: k, value: object}
+	if options != nil {
+		if options.Expiration > 0 && (ttl <= 0 || options.Expiration < ttl) {
+			ttl = options.Expiration
+		}
+		e.tags = options.Tags
+	}
+	if ttl > 0 {
+		e.expires = time.Now().Add(ttl)
+	}
+
+	c.entries[k] = e
+	for _, tag := range e.tags {
+		if c.tags[tag] == nil {
+			c.tags[tag] = make(map[string]struct{})
+		}
+		c.tags[tag][k] = struct{}{}
+	}
+	c.policy.add(e)
+	return nil
+}
+
+func (c *localCache) Delete(_ context.Context, key interface{}) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if e, ok := c.entries[fmt.Sprint(key)]; ok {
+		c.remove(e)
+	}
+	return nil
+}
+
+func (c *localCache) Invalidate(_ context.Context, options store.InvalidateOptions) error {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, tag := range options.Tags {
+		for key := range c.tags[tag] {
+			if e, ok := c.entries[key]; ok {
+				c.remove(e)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *localCache) Clear(context.Context) error {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, e := range c.entries {
+		c.remove(e)
+	}
+	return nil
+}
+
+func (c *localCache) GetType() string {
+	return "local"
+}
+
+func (c *localCache) remove(e *localEntry) {
+	delete(c.entries, e.key)
+	for _, tag := range e.tags {
+		delete(c.tags[tag], e.key)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+	c.policy.remove(e)
+}
+
+// evictionPolicy orders entries for eviction.
+type evictionPolicy interface {
+	add(e *localEntry)
+	touch(e *localEntry)
+	remove(e *localEntry)
+	victim() *localEntry
+}
+
+type lruPolicy struct {
+	list *list.List
+}
+
+func (p *lruPolicy) add(e *localEntry) {
+	e.element = p.list.PushFront(e)
+}
+
+func (p *lruPolicy) touch(e *localEntry) {
+	p.list.MoveToFront(e.element)
+}
+
+func (p *lruPolicy) remove(e *localEntry) {
+	p.list.Remove(e.element)
+}
+
+func (p *lruPolicy) victim() *localEntry {
+	return p.list.Back().Value.(*localEntry)
+}
+
+// lfuPolicy is a min-heap of entries by hits, ties are broken by the least recent use.
+type lfuPolicy struct {
+	entries []*localEntry
+	clock   uint64
+}
+
+func (p *lfuPolicy) add(e *localEntry) {
+	p.clock++
+	e.used = p.clock
+	heap.Push(p, e)
+}
+
+func (p *lfuPolicy) touch(e *localEntry) {
+	p.clock++
+	e.hits++
+	e.used = p.clock
+	heap.Fix(p, e.index)
+}
+
+func (p *lfuPolicy) remove(e *localEntry) {
+	heap.Remove(p, e.index)
+}
+
+func (p *lfuPolicy) victim() *localEntry {
+	return p.entries[0]
+}
+
+func (p *lfuPolicy) Len() int {
+	return len(p.entries)
+}
+
+func (p *lfuPolicy) Less(i, j int) bool {
+	if p.entries[i].hits != p.entries[j].hits {
+		return p.entries[i].hits < p.entries[j].hits
+	}
+	return p.entries[i].used < p.entries[j].used
+}
+
+func (p *lfuPolicy) Swap(i, j int) {
+	p.entries[i], p.entries[j] = p.entries[j], p.entries[i]
+	p.entries[i].index = i
+	p.entries[j].index = j
+}
+
+func (p *lfuPolicy) Push(x any) {
+	e := x.(*localEntry)
+	e.index = len(p.entries)
+	p.entries = append(p.entries, e)
+}
+
+func (p *lfuPolicy) Pop() any {
+	last := len(p.entries) - 1
+	e := p.entries[last]
+	p.entries[last] = nil
+	p.entries = p.entries[:last]
+	return e
+}
@@ -0,0 +1,57 @@
+package adapters
+
+import (
+	"context"
+	"github.com/eko/gocache/v2/store"
+	"testing"
+	"time"
+)
+
+func Test_localCache_Evict(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		evicted string
+	}{
+		{name: "lru evicts least recently used", policy: PolicyLRU, evicted: "b"},
+		{name: "lfu evicts least frequently used", policy: PolicyLFU, evicted: "c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := NewLocalCache(3, 0, tt.policy)
+			for _, key := range []string{"a", "b", "c"} {
+				_ = c.Set(ctx, key, key, nil)
+			}
+			// b is used least recently, c least frequently
+			for _, key := range []string{"b", "b", "a", "a", "c"} {
+				_, _ = c.Get(ctx, key)
+			}
+			_ = c.Set(ctx, "d", "d", nil)
+
+			for _, key := range []string{"a", "b", "c", "d"} {
+				value, _ := c.Get(ctx, key)
+				if (value == nil) != (key == tt.evicted) {
+					t.Errorf("Get(%s) = %v, want %s evicted", key, value, tt.evicted)
+				}
+			}
+		})
+	}
+}
+
+func Test_localCache_ExpireAndInvalidate(t *testing.T) {
+	ctx := context.Background()
+	c := NewLocalCache(10, time.Minute, PolicyLRU)
+	_ = c.Set(ctx, "short", 1, &store.Options{Expiration: time.Millisecond})
+	_ = c.Set(ctx, "tagged", 2, &store.Options{Tags: []string{"users"}})
+	_ = c.Set(ctx, "kept", 3, nil)
+
+	time.Sleep(2 * time.Millisecond)
+	_ = c.Invalidate(ctx, store.InvalidateOptions{Tags: []string{"users"}})
+
+	for key, want := range map[string]interface{}{"short": nil, "tagged": nil, "kept": 3} {
+		if got, _ := c.Get(ctx, key); got != want {
+			t.Errorf("Get(%s) = %v, want %v", key, got, want)
+		}
+	}
+}
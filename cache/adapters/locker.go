@@ -0,0 +1,193 @@
+package adapters
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"github.com/N-Vokhmyanin/go-framework/cache"
+	"github.com/bsm/redislock"
+	"sync"
+	"time"
+)
+
+type redisLocker struct {
+	client *redislock.Client
+}
+
+var _ cache.Locker = (*redisLocker)(nil)
+var _ cache.Lock = (*redislock.Lock)(nil)
+
+// NewRedisLocker creates a locker of locks shared by all instances using the redis client.
+func NewRedisLocker(client redislock.RedisClient) cache.Locker {
+	return WrapRedislock(redislock.New(client))
+}
+
+// WrapRedislock adapts a redislock client to cache.Locker, whose locks are cache.Lock interfaces.
+//
+//goland:noinspection GoUnusedExportedFunction
+func WrapRedislock(client *redislock.Client) cache.Locker {
+	return &redisLocker{client: client}
+}
+
+func (l *redisLocker) Obtain(ctx context.Context, key string, ttl time.Duration, opt *redislock.Options) (cache.Lock, error) {
+	lock, err := l.client.Obtain(ctx, key, ttl, opt)
+	if err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
+
+// memoryLocker keeps locks in a map, they are held within the process only.
+type memoryLocker struct {
+	sync.Mutex
+	locks map[string]memoryLockEntry
+}
+
+type memoryLockEntry struct {
+	value   string
+	expires time.Time
+}
+
+var _ cache.Locker = (*memoryLocker)(nil)
+
+// NewMemoryLocker creates an in-process locker, locks are held within the process only.
+func NewMemoryLocker() cache.Locker {
+	return &memoryLocker{locks: make(map[string]memoryLockEntry)}
+}
+
+// Obtain retries by the retry strategy of options until the ttl or the deadline of ctx, like redislock.
+func (l *memoryLocker) Obtain(ctx context.Context, key string, ttl time.Duration, opt *redislock.Options) (cache.Lock, error) {
+	token, err := randomLockToken()
+	if err != nil {
+		return nil, err
+	}
+	lock := &memoryLock{locker: l, key: key, token: token}
+	retry := redislock.NoRetry()
+	if opt != nil {
+		lock.metadata = opt.Metadata
+		if opt.RetryStrategy != nil {
+			retry = opt.RetryStrategy
+		}
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ttl)
+		defer cancel()
+	}
+
+	var timer *time.Timer
+	for {
+		if l.obtain(key, lock.value(), ttl) {
+			return lock, nil
+		}
+
+		backoff := retry.NextBackoff()
+		if backoff < 1 {
+			return nil, redislock.ErrNotObtained
+		}
+		if timer == nil {
+			timer = time.NewTimer(backoff)
+			defer timer.Stop()
+		} else {
+			timer.Reset(backoff)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, redislock.ErrNotObtained
+		case <-timer.C:
+		}
+	}
+}
+
+func (l *memoryLocker) obtain(key, value string, ttl time.Duration) bool {
+	l.Lock()
+	defer l.Unlock()
+
+	if _, ok := l.held(key); ok {
+		return false
+	}
+	l.locks[key] = memoryLockEntry{value: value, expires: time.Now().Add(ttl)}
+	return true
+}
+
+// held returns the lock entry of the key unless it expired, must be called with the mutex held.
+func (l *memoryLocker) held(key string) (memoryLockEntry, bool) {
+	entry, ok := l.locks[key]
+	if ok && !time.Now().Before(entry.expires) {
+		delete(l.locks, key)
+		return entry, false
+	}
+	return entry, ok
+}
+
+type memoryLock struct {
+	locker   *memoryLocker
+	key      string
+	token    string
+	metadata string
+}
+
+var _ cache.Lock = (*memoryLock)(nil)
+
+func (l *memoryLock) Key() string {
+	return l.key
+}
+
+func (l *memoryLock) Token() string {
+	return l.token
+}
+
+func (l *memoryLock) Metadata() string {
+	return l.metadata
+}
+
+func (l *memoryLock) value() string {
+	return l.token + l.metadata
+}
+
+// TTL returns the remaining ttl, zero when the lock is not held anymore.
+func (l *memoryLock) TTL(context.Context) (time.Duration, error) {
+	l.locker.Lock()
+	defer l.locker.Unlock()
+
+	entry, ok := l.locker.held(l.key)
+	if !ok || entry.value != l.value() {
+		return 0, nil
+	}
+	return time.Until(entry.expires), nil
+}
+
+func (l *memoryLock) Refresh(_ context.Context, ttl time.Duration, _ *redislock.Options) error {
+	l.locker.Lock()
+	defer l.locker.Unlock()
+
+	entry, ok := l.locker.held(l.key)
+	if !ok || entry.value != l.value() {
+		return redislock.ErrNotObtained
+	}
+	entry.expires = time.Now().Add(ttl)
+	l.locker.locks[l.key] = entry
+	return nil
+}
+
+func (l *memoryLock) Release(context.Context) error {
+	l.locker.Lock()
+	defer l.locker.Unlock()
+
+	entry, ok := l.locker.held(l.key)
+	if !ok || entry.value != l.value() {
+		return redislock.ErrLockNotHeld
+	}
+	delete(l.locker.locks, l.key)
+	return nil
+}
+
+func randomLockToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
@@ -0,0 +1,71 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"github.com/N-Vokhmyanin/go-framework/cache"
+	"github.com/bsm/redislock"
+	"github.com/go-redis/redis/v8"
+	"testing"
+	"time"
+)
+
+func Test_NewMemoryLocker(t *testing.T) {
+	ctx := context.Background()
+	locker := NewMemoryLocker()
+
+	lock, err := locker.Obtain(ctx, "key", time.Minute, nil)
+	if err != nil {
+		t.Fatalf("Obtain() error = %v", err)
+	}
+	if _, err = locker.Obtain(ctx, "key", time.Minute, nil); !errors.Is(err, redislock.ErrNotObtained) {
+		t.Errorf("Obtain() of held lock error = %v, want %v", err, redislock.ErrNotObtained)
+	}
+	if ttl, _ := lock.TTL(ctx); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("TTL() = %s, want up to a minute", ttl)
+	}
+	if err = lock.Refresh(ctx, time.Hour, nil); err != nil {
+		t.Errorf("Refresh() error = %v", err)
+	}
+	if ttl, _ := lock.TTL(ctx); ttl <= time.Minute {
+		t.Errorf("TTL() after Refresh() = %s, want up to an hour", ttl)
+	}
+	if err = lock.Release(ctx); err != nil {
+		t.Errorf("Release() error = %v", err)
+	}
+	if err = lock.Release(ctx); !errors.Is(err, redislock.ErrLockNotHeld) {
+		t.Errorf("Release() of released lock error = %v, want %v", err, redislock.ErrLockNotHeld)
+	}
+	if err = lock.Refresh(ctx, time.Minute, nil); !errors.Is(err, redislock.ErrNotObtained) {
+		t.Errorf("Refresh() of released lock error = %v, want %v", err, redislock.ErrNotObtained)
+	}
+
+	held, _ := locker.Obtain(ctx, "retried", 20*time.Millisecond, nil)
+	retry := &redislock.Options{RetryStrategy: redislock.LinearBackoff(5 * time.Millisecond), Metadata: "meta"}
+	lock, err = locker.Obtain(ctx, "retried", time.Minute, retry)
+	if err != nil {
+		t.Fatalf("Obtain() with retries error = %v", err)
+	}
+	if lock.Metadata() != "meta" {
+		t.Errorf("Metadata() = %q, want meta", lock.Metadata())
+	}
+	if err = held.Release(ctx); !errors.Is(err, redislock.ErrLockNotHeld) {
+		t.Errorf("Release() of expired lock obtained by another owner error = %v, want %v", err, redislock.ErrLockNotHeld)
+	}
+
+	if _, err = locker.Obtain(ctx, "expiring", time.Millisecond, nil); err != nil {
+		t.Fatalf("Obtain() error = %v", err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if _, err = locker.Obtain(ctx, "expiring", time.Minute, nil); err != nil {
+		t.Errorf("Obtain() of expired lock error = %v", err)
+	}
+}
+
+func Test_WrapRedislock(t *testing.T) {
+	var locker cache.Locker = WrapRedislock(redislock.New(redis.NewClient(&redis.Options{Addr: "127.0.0.1:1"})))
+	lock, err := locker.Obtain(context.Background(), "key", time.Second, nil)
+	if err == nil || lock != nil {
+		t.Errorf("Obtain() without redis = %v, %v, want nil lock and an error", lock, err)
+	}
+}
@@ -24,6 +24,18 @@ type JsonCacheInterface interface {
 	Marshal(ctx context.Context, key string, value any, options *store.Options) error
 }
 
+// Locker obtains locks, redislock.ErrNotObtained is returned when a lock is held by another owner.
+// Wrap a *redislock.Client with adapters.WrapRedislock to use it as a Locker.
 type Locker interface {
-	Obtain(ctx context.Context, key string, ttl time.Duration, opt *redislock.Options) (*redislock.Lock, error)
+	Obtain(ctx context.Context, key string, ttl time.Duration, opt *redislock.Options) (Lock, error)
+}
+
+// Lock is an obtained lock, *redislock.Lock implements it.
+type Lock interface {
+	Key() string
+	Token() string
+	Metadata() string
+	TTL(ctx context.Context) (time.Duration, error)
+	Refresh(ctx context.Context, ttl time.Duration, opt *redislock.Options) error
+	Release(ctx context.Context) error
 }
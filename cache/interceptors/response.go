@@ -0,0 +1,147 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/N-Vokhmyanin/go-framework/cache"
+	"github.com/eko/gocache/v2/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"strings"
+	"time"
+)
+
+const (
+	// NoCacheHeader makes the request skip the response cache, unless its value is "false" or "0".
+	NoCacheHeader = "x-no-cache"
+	// CacheStatusHeader is set to "hit" or "miss" on responses of cached methods.
+	CacheStatusHeader = "x-cache"
+
+	responseCachePrefix = "grpc-response"
+)
+
+// ResponseCacheRule configures caching of responses of a method.
+type ResponseCacheRule struct {
+	TTL time.Duration
+	// Tags invalidate the cached responses, see InvalidateResponses.
+	Tags []string
+	// Headers are metadata keys whose values are a part of the cache key, like the language or the tenant.
+	Headers []string
+}
+
+// ResponseCacheUnaryServerInterceptor returns cached responses of methods with rules, keyed by full method names.
+// Responses are cached by the request and the rule headers, only successful responses are stored.
+func ResponseCacheUnaryServerInterceptor(c cache.CacheInterface, rules map[string]ResponseCacheRule) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		rule, ok := rules[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		if noCache(md) {
+			return handler(ctx, req)
+		}
+		key, err := responseCacheKey(info.FullMethod, msg, rule.Headers, md)
+		if err != nil {
+			return handler(ctx, req)
+		}
+
+		if resp, hit := cachedResponse(ctx, c, key); hit {
+			_ = grpc.SetHeader(ctx, metadata.Pairs(CacheStatusHeader, "hit"))
+			return resp, nil
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(CacheStatusHeader, "miss"))
+		if respMsg, ok := resp.(proto.Message); ok {
+			storeResponse(ctx, c, key, respMsg, rule)
+		}
+		return resp, nil
+	}
+}
+
+// InvalidateResponses removes cached responses of methods with any of the tags, write handlers call it after changes.
+//
+//goland:noinspection GoUnusedExportedFunction
+func InvalidateResponses(ctx context.Context, c cache.CacheInterface, tags ...string) error {
+	return c.Invalidate(ctx, store.InvalidateOptions{Tags: tags})
+}
+
+func noCache(md metadata.MD) bool {
+	for _, value := range md.Get(NoCacheHeader) {
+		if value != "false" && value != "0" {
+			return true
+		}
+	}
+	return false
+}
+
+// responseCacheKey hashes the method, the deterministically marshalled request and the header values.
+func responseCacheKey(method string, req proto.Message, headers []string, md metadata.MD) (string, error) {
+	body, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	for _, header := range headers {
+		hash.Write([]byte{0})
+		hash.Write([]byte(strings.Join(md.Get(header), ",")))
+	}
+	return responseCachePrefix + "__" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// cachedResponse returns the response stored as an Any message, so its type is resolved from the registry.
+func cachedResponse(ctx context.Context, c cache.CacheInterface, key string) (proto.Message, bool) {
+	cached, err := c.Get(ctx, key)
+	if err != nil {
+		return nil, false
+	}
+	var data []byte
+	switch v := cached.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return nil, false
+	}
+
+	wrapped := &anypb.Any{}
+	if err = proto.Unmarshal(data, wrapped); err != nil {
+		return nil, false
+	}
+	resp, err := wrapped.UnmarshalNew()
+	if err != nil {
+		return nil, false
+	}
+	return resp, true
+}
+
+func storeResponse(ctx context.Context, c cache.CacheInterface, key string, resp proto.Message, rule ResponseCacheRule) {
+	wrapped, err := anypb.New(resp)
+	if err != nil {
+		return
+	}
+	data, err := proto.Marshal(wrapped)
+	if err != nil {
+		return
+	}
+	// stored as a string, as redis returns strings
+	_ = c.Set(ctx, key, string(data), &store.Options{
+		Expiration: rule.TTL,
+		Tags:       rule.Tags,
+	})
+}
@@ -0,0 +1,73 @@
+package interceptors
+
+import (
+	"context"
+	"github.com/N-Vokhmyanin/go-framework/cache/adapters"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"testing"
+	"time"
+)
+
+type testStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *testStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func Test_ResponseCacheUnaryServerInterceptor(t *testing.T) {
+	c := adapters.NewAdapter(adapters.NewMemoryCache(), "app")
+	interceptor := ResponseCacheUnaryServerInterceptor(c, map[string]ResponseCacheRule{
+		"/users.Users/Get": {TTL: time.Minute, Tags: []string{"users"}, Headers: []string{"x-lang"}},
+	})
+
+	var calls int
+	handler := func(_ context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return wrapperspb.String(req.(*wrapperspb.StringValue).GetValue() + "-response"), nil
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		md         metadata.MD
+		invalidate bool
+		calls      int
+		status     string
+	}{
+		{name: "first request misses", method: "/users.Users/Get", md: metadata.Pairs("x-lang", "en"), calls: 1, status: "miss"},
+		{name: "same request hits", method: "/users.Users/Get", md: metadata.Pairs("x-lang", "en"), calls: 1, status: "hit"},
+		{name: "other header value misses", method: "/users.Users/Get", md: metadata.Pairs("x-lang", "de"), calls: 2, status: "miss"},
+		{name: "opted out request skips cache", method: "/users.Users/Get", md: metadata.Pairs("x-lang", "en", NoCacheHeader, "1"), calls: 3},
+		{name: "method without rule is not cached", method: "/users.Users/Update", calls: 4},
+		{name: "invalidated response misses", method: "/users.Users/Get", md: metadata.Pairs("x-lang", "en"), invalidate: true, calls: 5, status: "miss"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.invalidate {
+				if err := InvalidateResponses(context.Background(), c, "users"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			stream := &testStream{}
+			ctx := grpc.NewContextWithServerTransportStream(metadata.NewIncomingContext(context.Background(), tt.md), stream)
+
+			resp, err := interceptor(ctx, wrapperspb.String("john"), &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if err != nil || !proto.Equal(resp.(proto.Message), wrapperspb.String("john-response")) {
+				t.Errorf("interceptor() = %v, %v", resp, err)
+			}
+			if calls != tt.calls {
+				t.Errorf("handler calls = %d, want %d", calls, tt.calls)
+			}
+			if got := stream.header.Get(CacheStatusHeader); (len(got) == 0 && tt.status != "") || (len(got) > 0 && got[0] != tt.status) {
+				t.Errorf("%s header = %v, want %q", CacheStatusHeader, got, tt.status)
+			}
+		})
+	}
+}
@@ -1,29 +1,40 @@
 package providers
 
 import (
+	"github.com/N-Vokhmyanin/go-framework/application/config"
 	"github.com/N-Vokhmyanin/go-framework/cache"
 	cacheAdapters "github.com/N-Vokhmyanin/go-framework/cache/adapters"
 	cacheInterceptors "github.com/N-Vokhmyanin/go-framework/cache/interceptors"
 	"github.com/N-Vokhmyanin/go-framework/contracts"
 	"github.com/N-Vokhmyanin/go-framework/cron"
+	"github.com/N-Vokhmyanin/go-framework/errors"
+	"github.com/N-Vokhmyanin/go-framework/logger"
 	"github.com/N-Vokhmyanin/go-framework/queue"
 	"github.com/N-Vokhmyanin/go-framework/transport"
-	"github.com/bsm/redislock"
+	"github.com/N-Vokhmyanin/go-framework/utils/di"
 	goCache "github.com/eko/gocache/v2/cache"
 	"github.com/eko/gocache/v2/store"
 	"github.com/go-redis/redis/v8"
+	"time"
 )
 
 type provider struct {
-	redisAddr string
-	redisDb   int
+	driver string
+	redis  redisConfig
+
+	l1Size   int
+	l1TTL    time.Duration
+	l1Policy string
 
 	withGrpcInterceptor       bool
+	responseCacheRules        map[string]cacheInterceptors.ResponseCacheRule
 	withJobHandlerMiddleware  bool
 	withCronHandlerMiddleware bool
 }
 
 var _ contracts.Provider = (*provider)(nil)
+var _ contracts.ProviderWithOptionalDependencies = (*provider)(nil)
+var _ contracts.ConfigValidator = (*provider)(nil)
 
 //goland:noinspection GoUnusedExportedFunction
 func NewProvider() contracts.Provider {
@@ -35,6 +46,12 @@ func (p *provider) WithGrpcInterceptor() *provider {
 	return p
 }
 
+// WithResponseCache caches responses of gRPC methods with rules, keyed by full method names.
+func (p *provider) WithResponseCache(rules map[string]cacheInterceptors.ResponseCacheRule) *provider {
+	p.responseCacheRules = rules
+	return p
+}
+
 func (p *provider) WithJobHandlerMiddleware() *provider {
 	p.withJobHandlerMiddleware = true
 	return p
@@ -53,38 +70,110 @@ func (p *provider) WithAllInterceptors() *provider {
 }
 
 func (p *provider) Config(c contracts.ConfigSet) {
-	c.StringVar(&p.redisAddr, "REDIS_ADDR", "localhost:6379", "redis address with port")
-	c.IntVar(&p.redisDb, "REDIS_DB", 0, "redis db number")
+	c.StringVar(&p.driver, "CACHE_DRIVER", DriverRedis, "cache backend: memory, redis, redis-sentinel, redis-cluster or noop")
+	config.Rules(c, "CACHE_DRIVER", config.OneOf(DriverMemory, DriverRedis, DriverRedisSentinel, DriverRedisCluster, DriverNoop))
+
+	c.StringVar(&p.redis.addrs, "REDIS_ADDR", "localhost:6379", "redis address with port, comma separated sentinel or cluster node addresses")
+	c.IntVar(&p.redis.db, "REDIS_DB", 0, "redis db number")
+	c.StringVar(&p.redis.username, "REDIS_USERNAME", "", "redis username")
+	config.SecretVar(c, &p.redis.password, "REDIS_PASSWORD", "", "redis password")
+	c.BoolVar(&p.redis.tls, "REDIS_TLS", false, "connect to redis with TLS")
+	c.BoolVar(&p.redis.tlsInsecure, "REDIS_TLS_INSECURE", false, "skip verification of the redis TLS certificate")
+	c.StringVar(&p.redis.sentinelMaster, "REDIS_SENTINEL_MASTER", "", "redis sentinel master name")
+	config.SecretVar(c, &p.redis.sentinelPassword, "REDIS_SENTINEL_PASSWORD", "", "redis sentinel password")
+	c.IntVar(&p.redis.poolSize, "REDIS_POOL_SIZE", 0, "redis connections per node, 0 is 10 per CPU")
+	c.IntVar(&p.redis.minIdleConns, "REDIS_MIN_IDLE_CONNS", 0, "redis idle connections kept open")
+	config.Rules(c, "REDIS_POOL_SIZE", config.Min(0))
+	config.Rules(c, "REDIS_MIN_IDLE_CONNS", config.Min(0))
+
+	c.IntVar(&p.l1Size, "CACHE_L1_SIZE", 0, "entries of the in-process cache in front of redis drivers, 0 disables it, memory and noop drivers ignore it")
+	c.DurationVar(&p.l1TTL, "CACHE_L1_TTL", time.Minute, "max time entries are kept in the in-process cache")
+	c.StringVar(&p.l1Policy, "CACHE_L1_POLICY", cacheAdapters.PolicyLRU, "in-process cache eviction policy, lru or lfu")
+	config.Rules(c, "CACHE_L1_SIZE", config.Min(0))
+	config.Rules(c, "CACHE_L1_POLICY", config.OneOf(cacheAdapters.PolicyLRU, cacheAdapters.PolicyLFU))
 }
 
-func (p *provider) Boot(a contracts.Application) {
-	a.Singleton(func() *redis.Client {
-		redisOptions := &redis.Options{
-			Addr: p.redisAddr,
-			DB:   p.redisDb,
-		}
-		return redis.NewClient(redisOptions)
-	})
+// ValidateConfig requires the master name for sentinels.
+func (p *provider) ValidateConfig(string) error {
+	if p.driver == DriverRedisSentinel && p.redis.sentinelMaster == "" {
+		return errors.NewFieldValidationError("REDIS_SENTINEL_MASTER", "is required by the %s driver", DriverRedisSentinel)
+	}
+	return nil
+}
 
-	a.Singleton(func(redisClient *redis.Client) goCache.CacheInterface {
-		return goCache.New(store.NewRedis(redisClient, nil))
-	})
+func (p *provider) Boot(a contracts.Application) {
+	switch p.driver {
+	case DriverMemory, DriverNoop:
+		p.bootLocal(a)
+	default:
+		p.bootRedis(a)
+	}
 
 	a.Singleton(func(base goCache.CacheInterface) cache.CacheInterface {
 		return cacheAdapters.NewAdapter(base, a.Name())
 	})
 
 	a.Singleton(cacheAdapters.NewJsonCache)
+}
+
+// bootLocal binds the in-process cache and locker, which need no redis.
+func (p *provider) bootLocal(a contracts.Application) {
+	a.Singleton(func() goCache.CacheInterface {
+		if p.driver == DriverNoop {
+			return cacheAdapters.NoopStoreInstance
+		}
+		return cacheAdapters.NewMemoryCache()
+	})
+	a.Singleton(cacheAdapters.NewMemoryLocker)
+}
+
+// bootRedis binds redis.UniversalClient for every redis driver, *redis.Client is not bound by the cluster driver,
+// which creates a *redis.ClusterClient.
+func (p *provider) bootRedis(a contracts.Application) {
+	a.Singleton(func() redis.UniversalClient {
+		return newRedisClient(p.driver, p.redis)
+	})
+	if p.driver != DriverRedisCluster {
+		a.Singleton(func(client redis.UniversalClient) *redis.Client {
+			return client.(*redis.Client)
+		})
+	}
+
+	if p.l1Size > 0 {
+		a.Singleton(func(redisClient redis.UniversalClient, log logger.Logger) cacheAdapters.Invalidator {
+			return cacheAdapters.NewRedisInvalidator(redisClient, a.Name()+".cache.invalidations", log)
+		})
+		a.Singleton(func(redisClient redis.UniversalClient, invalidator cacheAdapters.Invalidator) goCache.CacheInterface {
+			return cacheAdapters.NewTwoLevelCache(
+				cacheAdapters.NewLocalCache(p.l1Size, p.l1TTL, p.l1Policy),
+				goCache.New(store.NewRedis(redisClient, nil)),
+				invalidator,
+			)
+		})
+	} else {
+		a.Singleton(func(redisClient redis.UniversalClient) goCache.CacheInterface {
+			return goCache.New(store.NewRedis(redisClient, nil))
+		})
+	}
 
-	a.Singleton(func(redisClient *redis.Client) cache.Locker {
-		return redislock.New(redisClient)
+	a.Singleton(func(redisClient redis.UniversalClient) cache.Locker {
+		return cacheAdapters.NewRedisLocker(redisClient)
 	})
 }
 
+func (p *provider) After() []string {
+	return []string{
+		transport.TransportProviderName,
+		queue.AmqpProviderName,
+		cron.CronProviderName,
+	}
+}
+
 func (p *provider) Register(a contracts.Application) {
 	if p.withGrpcInterceptor {
-		a.Make(func(grpcServer transport.GrpcServer) {
-			if grpcServer == nil {
+		a.Make(func(server di.Optional[transport.GrpcServer]) {
+			grpcServer, ok := server.Get()
+			if !ok {
 				return
 			}
 			grpcServer.WithOptions(
@@ -92,9 +181,21 @@ func (p *provider) Register(a contracts.Application) {
 			)
 		})
 	}
+	if len(p.responseCacheRules) > 0 {
+		a.Make(func(server di.Optional[transport.GrpcServer], c cache.CacheInterface) {
+			grpcServer, ok := server.Get()
+			if !ok {
+				return
+			}
+			grpcServer.WithOptions(
+				transport.WithUnaryInterceptors(cacheInterceptors.ResponseCacheUnaryServerInterceptor(c, p.responseCacheRules)),
+			)
+		})
+	}
 	if p.withJobHandlerMiddleware {
-		a.Make(func(queueMgr queue.Manager) {
-			if queueMgr == nil {
+		a.Make(func(manager di.Optional[queue.Manager]) {
+			queueMgr, ok := manager.Get()
+			if !ok {
 				return
 			}
 			queueMgr.Middleware(
@@ -103,8 +204,9 @@ func (p *provider) Register(a contracts.Application) {
 		})
 	}
 	if p.withCronHandlerMiddleware {
-		a.Make(func(cronSvc cron.Service) {
-			if cronSvc == nil {
+		a.Make(func(service di.Optional[cron.Service]) {
+			cronSvc, ok := service.Get()
+			if !ok {
 				return
 			}
 			cronSvc.Middleware(
@@ -0,0 +1,57 @@
+package providers
+
+import (
+	"crypto/tls"
+	"github.com/go-redis/redis/v8"
+	"strings"
+)
+
+const (
+	DriverMemory        = "memory"
+	DriverRedis         = "redis"
+	DriverRedisSentinel = "redis-sentinel"
+	DriverRedisCluster  = "redis-cluster"
+	DriverNoop          = "noop"
+)
+
+type redisConfig struct {
+	addrs            string
+	db               int
+	username         string
+	password         string
+	tls              bool
+	tlsInsecure      bool
+	sentinelMaster   string
+	sentinelPassword string
+	poolSize         int
+	minIdleConns     int
+}
+
+// newRedisClient creates a client of a single node, of nodes monitored by sentinels or of a cluster.
+func newRedisClient(driver string, cfg redisConfig) redis.UniversalClient {
+	opts := &redis.UniversalOptions{
+		Addrs:            strings.Split(cfg.addrs, ","),
+		DB:               cfg.db,
+		Username:         cfg.username,
+		Password:         cfg.password,
+		MasterName:       cfg.sentinelMaster,
+		SentinelPassword: cfg.sentinelPassword,
+		PoolSize:         cfg.poolSize,
+		MinIdleConns:     cfg.minIdleConns,
+	}
+	for i, addr := range opts.Addrs {
+		opts.Addrs[i] = strings.TrimSpace(addr)
+	}
+	if cfg.tls {
+		opts.TLSConfig = &tls.Config{InsecureSkipVerify: cfg.tlsInsecure}
+	}
+
+	switch driver {
+	case DriverRedisSentinel:
+		return redis.NewFailoverClient(opts.Failover())
+	case DriverRedisCluster:
+		return redis.NewClusterClient(opts.Cluster())
+	default:
+		return redis.NewClient(opts.Simple())
+	}
+}
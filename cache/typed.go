@@ -0,0 +1,378 @@
+package cache
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/N-Vokhmyanin/go-framework/logger"
+	"github.com/bsm/redislock"
+	"github.com/eko/gocache/v2/store"
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	// lockPollInterval is how often the cache is checked while another instance loads the value.
+	lockPollInterval = 50 * time.Millisecond
+	// defaultLoadTimeout limits loads shared by callers, see TypedLoadTimeoutOption.
+	defaultLoadTimeout = 30 * time.Second
+)
+
+// Typed stores values of type T encoded by a codec.
+type Typed[T any] interface {
+	// Get returns the cached value, false is returned when the key is missing or expired.
+	Get(ctx context.Context, key string) (T, bool, error)
+	// Set stores the value, zero ttl keeps the default expiration of the store.
+	Set(ctx context.Context, key string, value T, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	// GetOrLoad returns the cached value or the value of the loader, which is then stored for ttl.
+	// Concurrent loads of a key share one loader call, which is not cancelled with the ctx of any caller,
+	// each caller stops waiting when its own ctx is done. Only loader errors are returned,
+	// cache failures are logged and fall back to the loader.
+	GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader func(ctx context.Context) (T, error)) (T, error)
+}
+
+type typedOptions struct {
+	codec   Codec
+	prefix  string
+	log     logger.Logger
+	locker  Locker
+	lockTTL time.Duration
+	stale   time.Duration
+	beta    float64
+	timeout time.Duration
+}
+
+type TypedOption func(o *typedOptions)
+
+// TypedCodecOption sets the codec of values, JSON is used by default.
+//
+//goland:noinspection GoUnusedExportedFunction
+func TypedCodecOption(codec Codec) TypedOption {
+	return func(o *typedOptions) {
+		o.codec = codec
+	}
+}
+
+// TypedPrefixOption prefixes keys, in addition to the prefix of the base cache.
+//
+//goland:noinspection GoUnusedExportedFunction
+func TypedPrefixOption(prefix string) TypedOption {
+	return func(o *typedOptions) {
+		o.prefix = prefix
+	}
+}
+
+// TypedLoggerOption sets the logger of cache failures in GetOrLoad.
+//
+//goland:noinspection GoUnusedExportedFunction
+func TypedLoggerOption(log logger.Logger) TypedOption {
+	return func(o *typedOptions) {
+		o.log = log
+	}
+}
+
+// TypedLockerOption makes one instance load a missing key, others wait for its value up to ttl,
+// which also limits the time the lock is held.
+//
+//goland:noinspection GoUnusedExportedFunction
+func TypedLockerOption(locker Locker, ttl time.Duration) TypedOption {
+	return func(o *typedOptions) {
+		o.locker = locker
+		o.lockTTL = ttl
+	}
+}
+
+// TypedLoadTimeoutOption limits loads shared by callers of GetOrLoad and background refreshes,
+// 30 seconds by default, zero timeout does not limit them.
+//
+//goland:noinspection GoUnusedExportedFunction
+func TypedLoadTimeoutOption(timeout time.Duration) TypedOption {
+	return func(o *typedOptions) {
+		o.timeout = timeout
+	}
+}
+
+// TypedStaleOption keeps expired values for the duration, GetOrLoad returns them while refreshing in the background.
+//
+//goland:noinspection GoUnusedExportedFunction
+func TypedStaleOption(stale time.Duration) TypedOption {
+	return func(o *typedOptions) {
+		o.stale = stale
+	}
+}
+
+// TypedEarlyRefreshOption refreshes values in the background before they expire, with a probability
+// growing as the expiration nears and with the time the value took to load (XFetch).
+// Beta 1 is the usual choice, higher values refresh earlier.
+//
+//goland:noinspection GoUnusedExportedFunction
+func TypedEarlyRefreshOption(beta float64) TypedOption {
+	return func(o *typedOptions) {
+		o.beta = beta
+	}
+}
+
+type typed[T any] struct {
+	base  CacheInterface
+	group singleflight.Group
+	typedOptions
+}
+
+var _ Typed[any] = (*typed[any])(nil)
+
+//goland:noinspection GoUnusedExportedFunction
+func NewTyped[T any](base CacheInterface, opts ...TypedOption) Typed[T] {
+	c := &typed[T]{
+		base: base,
+		typedOptions: typedOptions{
+			codec:   JSONCodec,
+			log:     logger.GetNopLogger(),
+			timeout: defaultLoadTimeout,
+		},
+	}
+	for _, opt := range opts {
+		opt(&c.typedOptions)
+	}
+	return c
+}
+
+// entry is a cached value with the time it expires and the time it took to load.
+type entry[T any] struct {
+	value  T
+	expiry time.Time
+	delta  time.Duration
+}
+
+// expired reports whether the entry expired, entries without expiry never expire.
+func (e entry[T]) expired(now time.Time) bool {
+	return !e.expiry.IsZero() && !now.Before(e.expiry)
+}
+
+// early reports whether the entry should be refreshed before it expires, see TypedEarlyRefreshOption.
+func (e entry[T]) early(now time.Time, beta float64) bool {
+	if beta <= 0 || e.expiry.IsZero() || e.delta <= 0 {
+		return false
+	}
+	gap := time.Duration(float64(e.delta) * beta * -math.Log(1-rand.Float64()))
+	return !now.Add(gap).Before(e.expiry)
+}
+
+func (c *typed[T]) key(key string) string {
+	if c.prefix == "" {
+		return key
+	}
+	return fmt.Sprintf("%s__%s", c.prefix, key)
+}
+
+func (c *typed[T]) Get(ctx context.Context, key string) (value T, ok bool, err error) {
+	e, ok, err := c.get(ctx, key)
+	if !ok || e.expired(time.Now()) {
+		return value, false, err
+	}
+	return e.value, true, nil
+}
+
+func (c *typed[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	return c.set(ctx, key, entry[T]{value: value}, ttl)
+}
+
+func (c *typed[T]) Delete(ctx context.Context, key string) error {
+	return c.base.Delete(ctx, c.key(key))
+}
+
+func (c *typed[T]) GetOrLoad(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+	loader func(ctx context.Context) (T, error),
+) (T, error) {
+	e, ok, err := c.get(ctx, key)
+	if err != nil {
+		c.log.Warnw("cache get failed", "cache.key", key, zap.Error(err))
+	}
+	if ok {
+		now := time.Now()
+		switch {
+		case !e.expired(now) && !e.early(now, c.beta):
+			return e.value, nil
+		case !e.expired(now) || c.stale > 0:
+			go c.refresh(context.WithoutCancel(ctx), key, ttl, loader)
+			return e.value, nil
+		}
+	}
+
+	result := c.group.DoChan(c.key(key), func() (interface{}, error) {
+		loadCtx, cancel := c.loadContext(ctx)
+		defer cancel()
+		return c.load(loadCtx, key, ttl, loader, true)
+	})
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	case r := <-result:
+		if r.Err != nil {
+			var zero T
+			return zero, r.Err
+		}
+		return r.Val.(T), nil
+	}
+}
+
+// refresh loads the value in the background, unless it is loaded here or by another instance.
+func (c *typed[T]) refresh(ctx context.Context, key string, ttl time.Duration, loader func(ctx context.Context) (T, error)) {
+	// a separate flight, so loads of a missing key never get the result of a skipped refresh
+	_, err, _ := c.group.Do(c.key(key)+"__refresh", func() (interface{}, error) {
+		loadCtx, cancel := c.loadContext(ctx)
+		defer cancel()
+		return c.load(loadCtx, key, ttl, loader, false)
+	})
+	if err != nil {
+		c.log.Warnw("cache refresh failed", "cache.key", key, zap.Error(err))
+	}
+}
+
+// loadContext detaches a shared load from the caller, so a cancelled caller does not fail the others,
+// the load is limited by the load timeout instead.
+func (c *typed[T]) loadContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx = context.WithoutCancel(ctx)
+	if c.timeout > 0 {
+		return context.WithTimeout(ctx, c.timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// load calls the loader and stores its value. When another instance holds the lock of the key,
+// wait makes load poll the cache for its value, otherwise the current value is kept.
+func (c *typed[T]) load(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+	loader func(ctx context.Context) (T, error),
+	wait bool,
+) (value T, err error) {
+	if c.locker != nil {
+		lock, lockErr := c.locker.Obtain(ctx, c.key(key)+"__lock", c.lockTTL, nil)
+		switch {
+		case errors.Is(lockErr, redislock.ErrNotObtained):
+			if !wait {
+				return value, nil
+			}
+			if e, ok := c.await(ctx, key); ok {
+				return e.value, nil
+			}
+		case lockErr != nil:
+			c.log.Warnw("cache lock failed", "cache.key", key, zap.Error(lockErr))
+		case lock != nil:
+			defer func() { _ = lock.Release(context.WithoutCancel(ctx)) }()
+		}
+	}
+
+	start := time.Now()
+	if value, err = loader(ctx); err != nil {
+		return value, err
+	}
+	if err = c.set(ctx, key, entry[T]{value: value, delta: time.Since(start)}, ttl); err != nil {
+		c.log.Warnw("cache set failed", "cache.key", key, zap.Error(err))
+	}
+	return value, nil
+}
+
+// await polls the cache for a fresh value until the lock of the key expires.
+func (c *typed[T]) await(ctx context.Context, key string) (entry[T], bool) {
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+	timeout := time.NewTimer(c.lockTTL)
+	defer timeout.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return entry[T]{}, false
+		case <-timeout.C:
+			return entry[T]{}, false
+		case <-ticker.C:
+			if e, ok, _ := c.get(ctx, key); ok && !e.expired(time.Now()) {
+				return e, true
+			}
+		}
+	}
+}
+
+// get returns the entry, expired entries kept for stale reads included.
+func (c *typed[T]) get(ctx context.Context, key string) (e entry[T], ok bool, err error) {
+	cached, err := c.base.Get(ctx, c.key(key))
+	if errors.Is(err, redis.Nil) {
+		return e, false, nil
+	}
+	if err != nil {
+		return e, false, err
+	}
+
+	var data []byte
+	switch v := cached.(type) {
+	case nil:
+		return e, false, nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return e, false, fmt.Errorf("cache key %s holds %T, want encoded value", key, cached)
+	}
+
+	if e, err = c.decode(data); err != nil {
+		return e, false, err
+	}
+	return e, true, nil
+}
+
+// set stores the entry expiring after ttl, the store keeps it longer for stale reads.
+func (c *typed[T]) set(ctx context.Context, key string, e entry[T], ttl time.Duration) error {
+	expiration := ttl
+	if ttl > 0 {
+		e.expiry = time.Now().Add(ttl)
+		expiration += c.stale
+	}
+	data, err := c.encode(e)
+	if err != nil {
+		return err
+	}
+	// stored as a string, as redis returns strings
+	return c.base.Set(ctx, c.key(key), string(data), &store.Options{Expiration: expiration})
+}
+
+// entryHeaderSize is the size of the expiry and delta preceding the encoded value.
+const entryHeaderSize = 16
+
+func (c *typed[T]) encode(e entry[T]) ([]byte, error) {
+	value, err := c.codec.Marshal(e.value)
+	if err != nil {
+		return nil, err
+	}
+	var expiry int64
+	if !e.expiry.IsZero() {
+		expiry = e.expiry.UnixNano()
+	}
+	data := make([]byte, entryHeaderSize, entryHeaderSize+len(value))
+	binary.BigEndian.PutUint64(data[:8], uint64(expiry))
+	binary.BigEndian.PutUint64(data[8:], uint64(e.delta))
+	return append(data, value...), nil
+}
+
+func (c *typed[T]) decode(data []byte) (e entry[T], err error) {
+	if len(data) < entryHeaderSize {
+		return e, errors.New("cache entry is too short")
+	}
+	if expiry := int64(binary.BigEndian.Uint64(data[:8])); expiry != 0 {
+		e.expiry = time.Unix(0, expiry)
+	}
+	e.delta = time.Duration(binary.BigEndian.Uint64(data[8:entryHeaderSize]))
+	err = c.codec.Unmarshal(data[entryHeaderSize:], &e.value)
+	return e, err
+}
@@ -0,0 +1,233 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"github.com/N-Vokhmyanin/go-framework/cache"
+	"github.com/N-Vokhmyanin/go-framework/cache/adapters"
+	"github.com/bsm/redislock"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type user struct {
+	ID   int
+	Name string
+}
+
+func Test_Typed(t *testing.T) {
+	tests := []struct {
+		name  string
+		codec cache.Codec
+	}{
+		{name: "json", codec: cache.JSONCodec},
+		{name: "gob", codec: cache.GobCodec},
+		{name: "msgpack", codec: cache.MsgpackCodec},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			users := cache.NewTyped[user](
+				adapters.NewAdapter(adapters.NewMemoryCache(), "app"),
+				cache.TypedCodecOption(tt.codec),
+				cache.TypedPrefixOption("users"),
+			)
+
+			if _, ok, err := users.Get(ctx, "1"); ok || err != nil {
+				t.Errorf("Get() of missing key = %v, %v, want miss", ok, err)
+			}
+
+			var loads int
+			loader := func(context.Context) (user, error) {
+				loads++
+				return user{ID: 1, Name: "John"}, nil
+			}
+			for i := 0; i < 2; i++ {
+				got, err := users.GetOrLoad(ctx, "1", time.Minute, loader)
+				if err != nil || !reflect.DeepEqual(got, user{ID: 1, Name: "John"}) {
+					t.Errorf("GetOrLoad() = %v, %v", got, err)
+				}
+			}
+			if loads != 1 {
+				t.Errorf("GetOrLoad() loaded %d times, want 1", loads)
+			}
+
+			failed := errors.New("failed")
+			_, err := users.GetOrLoad(ctx, "2", time.Minute, func(context.Context) (user, error) {
+				return user{}, failed
+			})
+			if !errors.Is(err, failed) {
+				t.Errorf("GetOrLoad() error = %v, want %v", err, failed)
+			}
+			if _, ok, _ := users.Get(ctx, "2"); ok {
+				t.Errorf("GetOrLoad() cached the failed load")
+			}
+
+			if err = users.Delete(ctx, "1"); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok, _ := users.Get(ctx, "1"); ok {
+				t.Errorf("Get() after Delete() found the value")
+			}
+		})
+	}
+}
+
+func Test_ProtoCodec(t *testing.T) {
+	ctx := context.Background()
+	names := cache.NewTyped[*wrapperspb.StringValue](
+		adapters.NewAdapter(adapters.NewMemoryCache(), ""),
+		cache.TypedCodecOption(cache.ProtoCodec),
+	)
+	if err := names.Set(ctx, "name", wrapperspb.String("John"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	got, ok, err := names.Get(ctx, "name")
+	if err != nil || !ok || got.GetValue() != "John" {
+		t.Errorf("Get() = %v, %v, %v, want John", got, ok, err)
+	}
+}
+
+type busyLocker struct{}
+
+func (busyLocker) Obtain(context.Context, string, time.Duration, *redislock.Options) (cache.Lock, error) {
+	return nil, redislock.ErrNotObtained
+}
+
+func Test_Typed_GetOrLoad(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []cache.TypedOption
+		ttl   time.Duration
+		sleep time.Duration
+		// want is the value of the second GetOrLoad, background is the number of loads done after it
+		want       int
+		background int
+	}{
+		{
+			name:  "expired value is loaded",
+			ttl:   10 * time.Millisecond,
+			sleep: 20 * time.Millisecond,
+			want:  2,
+		},
+		{
+			name:       "stale value is returned and refreshed",
+			opts:       []cache.TypedOption{cache.TypedStaleOption(time.Minute)},
+			ttl:        10 * time.Millisecond,
+			sleep:      20 * time.Millisecond,
+			want:       1,
+			background: 2,
+		},
+		{
+			name:       "value is refreshed early",
+			opts:       []cache.TypedOption{cache.TypedEarlyRefreshOption(1e9)},
+			ttl:        time.Minute,
+			want:       1,
+			background: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			values := cache.NewTyped[int](adapters.NewAdapter(adapters.NewMemoryCache(), ""), tt.opts...)
+
+			var loads atomic.Int32
+			loader := func(context.Context) (int, error) {
+				time.Sleep(time.Millisecond)
+				return int(loads.Add(1)), nil
+			}
+			if _, err := values.GetOrLoad(ctx, "key", tt.ttl, loader); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(tt.sleep)
+
+			got, err := values.GetOrLoad(ctx, "key", tt.ttl, loader)
+			if err != nil || got != tt.want {
+				t.Errorf("GetOrLoad() = %v, %v, want %v", got, err, tt.want)
+			}
+			if tt.background > 0 {
+				deadline := time.Now().Add(time.Second)
+				for int(loads.Load()) < tt.background && time.Now().Before(deadline) {
+					time.Sleep(time.Millisecond)
+				}
+				if got := int(loads.Load()); got != tt.background {
+					t.Errorf("GetOrLoad() loaded %d times, want %d", got, tt.background)
+				}
+			}
+		})
+	}
+}
+
+func Test_Typed_GetOrLoad_Singleflight(t *testing.T) {
+	ctx := context.Background()
+	values := cache.NewTyped[int](adapters.NewAdapter(adapters.NewMemoryCache(), ""))
+
+	var loads atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = values.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) {
+				loads.Add(1)
+				time.Sleep(50 * time.Millisecond)
+				return 1, nil
+			})
+		}()
+	}
+	wg.Wait()
+	if got := loads.Load(); got != 1 {
+		t.Errorf("GetOrLoad() loaded %d times, want 1", got)
+	}
+}
+
+func Test_Typed_GetOrLoad_CancelledCaller(t *testing.T) {
+	values := cache.NewTyped[int](adapters.NewAdapter(adapters.NewMemoryCache(), ""))
+	loader := func(ctx context.Context) (int, error) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+			return 1, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	cancelled, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	first := make(chan error, 1)
+	go func() {
+		_, err := values.GetOrLoad(cancelled, "key", time.Minute, loader)
+		first <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	got, err := values.GetOrLoad(context.Background(), "key", time.Minute, loader)
+	if err != nil || got != 1 {
+		t.Errorf("GetOrLoad() of a waiter = %v, %v, want 1", got, err)
+	}
+	if err = <-first; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetOrLoad() of the cancelled caller error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func Test_Typed_GetOrLoad_Locked(t *testing.T) {
+	ctx := context.Background()
+	base := adapters.NewAdapter(adapters.NewMemoryCache(), "")
+	other := cache.NewTyped[int](base)
+	values := cache.NewTyped[int](base, cache.TypedLockerOption(busyLocker{}, time.Second))
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = other.Set(ctx, "key", 42, time.Minute)
+	}()
+	got, err := values.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) {
+		return 1, nil
+	})
+	if err != nil || got != 42 {
+		t.Errorf("GetOrLoad() = %v, %v, want value loaded by the lock holder", got, err)
+	}
+}
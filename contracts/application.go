@@ -15,11 +15,15 @@ type Application interface {
 	CanInit
 	CanStart
 	CanStop
+	Lifecycle
 	Container
 	Env() string
+	// IsProduction reports whether the application runs in the production environment.
+	IsProduction() bool
 	Name() string
 	Provide(items ...Provider)
 	Command(items ...*cli.Command)
+	Bootstrap(arguments []string) error
 	Run(args []string)
 }
 
@@ -28,3 +32,18 @@ type Provider interface {
 	Boot(a Application)
 	Register(a Application)
 }
+
+// ProviderWithName names the provider for dependency declarations, providers without a name are named by their type.
+type ProviderWithName interface {
+	ProviderName() string
+}
+
+// ProviderWithDependencies declares names of providers which must be provided and run before the provider.
+type ProviderWithDependencies interface {
+	DependsOn() []string
+}
+
+// ProviderWithOptionalDependencies declares names of providers which run before the provider when they are provided.
+type ProviderWithOptionalDependencies interface {
+	After() []string
+}
@@ -5,6 +5,15 @@ import "time"
 type Config interface {
 	New(provider string) ConfigSet
 	Parse(arguments []string)
+	Args() []string
+	Set(name, value string) error
+	// Validate checks values against the rules of the sets.
+	Validate() error
+	// Reload reads the values again, changed values with subscribers are validated, applied and passed to them,
+	// changes of other values take effect after a restart.
+	Reload() error
+	// Subscribe calls fn with the new value whenever the named value changes on reload.
+	Subscribe(name string, fn func(value string))
 	Visit(fn func(f ConfigFlag))
 }
 
@@ -19,10 +28,39 @@ type ConfigSet interface {
 	DurationVar(p *time.Duration, name string, value time.Duration, usage string)
 }
 
+// ConfigSetExt is implemented by config sets of the application, providers reach it
+// with the helpers of the application/config package, so other ConfigSet implementations keep working.
+type ConfigSetExt interface {
+	ConfigSet
+	// SecretVar binds a secret value, it is redacted in outputs and can be read from the file named in NAME_FILE.
+	SecretVar(p *string, name string, value string, usage string)
+	// Struct binds exported fields tagged with `env:"NAME" default:"value" usage:"text"`,
+	// nested structs are bound with their `prefix:"PREFIX_"` tag prepended to the names,
+	// fields tagged with `secret:"true"` are bound as secrets.
+	Struct(target interface{})
+	// Rules adds validation rules of the named value, they are checked once the config is parsed.
+	Rules(name string, rules ...ConfigRule)
+}
+
+// ConfigRule validates a config value, explicit reports whether any source sets the value.
+type ConfigRule func(value string, explicit bool) error
+
+// ConfigValidator is implemented by providers which cross-validate their config values once parsed,
+// env is the application environment, so checks like production-only safeguards can depend on it.
+type ConfigValidator interface {
+	ValidateConfig(env string) error
+}
+
 type ConfigFlag interface {
 	Name() string
 	Usage() string
 	Value() string
 	Default() string
 	Provider() string
+	// Source returns the layer the value came from: default, file:<path>, env, flag or code.
+	Source() string
+	// Secret reports whether the value is redacted in outputs.
+	Secret() bool
+	// Type returns the kind of the value: bool, int, uint, float, string, duration, url, list or map.
+	Type() string
 }
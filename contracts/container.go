@@ -1,8 +1,35 @@
 package contracts
 
+import "reflect"
+
 type Container interface {
 	Instances() []interface{}
 	Singleton(resolver interface{})
 	Transient(resolver interface{})
+	Scoped(resolver interface{})
+	SingletonNamed(name string, resolver interface{})
+	TransientNamed(name string, resolver interface{})
+	Tag(tag string, resolver interface{})
 	Make(receiver interface{}) []interface{}
+	MakeNamed(name string, receiver interface{})
+	MakeTagged(tag string) []interface{}
+	Inject(target interface{})
+	NewScope() Scope
+}
+
+// Scope resolves scoped bindings once per scope, Close disposes its scoped instances.
+type Scope interface {
+	Container
+	Close() error
+}
+
+// OptionalDependency is implemented by resolver arguments which may have no binding,
+// the container injects them empty instead of failing.
+type OptionalDependency interface {
+	DependencyType() reflect.Type
+}
+
+// OptionalDependencySetter receives the resolved dependency of OptionalDependency.
+type OptionalDependencySetter interface {
+	SetDependency(value interface{})
 }
@@ -1,8 +1,30 @@
 package contracts
 
-import "context"
+import (
+	"context"
+	"reflect"
+)
 
 type Dispatcher interface {
+	// Listen adds the func(ctx context.Context, e EventStruct) listener, see the events package for the typed API.
 	Listen(l interface{})
+	// Fire calls listeners of the event, listener errors are logged.
 	Fire(ctx context.Context, e interface{})
+	// Subscribe adds the listener of events of the type.
+	Subscribe(eventType reflect.Type, listener EventListener) Subscription
+	// Dispatch calls listeners of the event type and returns errors of synchronous listeners.
+	Dispatch(ctx context.Context, e interface{}) error
+}
+
+// EventListener handles events of one type, listeners with higher priority are called first,
+// async listeners run on the dispatcher worker pool after synchronous ones are called.
+type EventListener struct {
+	Handle   func(ctx context.Context, e interface{}) error
+	Priority int
+	Async    bool
+}
+
+// Subscription removes the listener from the dispatcher.
+type Subscription interface {
+	Unsubscribe()
 }
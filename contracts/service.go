@@ -1,5 +1,7 @@
 package contracts
 
+import "context"
+
 type CanBoot interface {
 	BootService()
 }
@@ -15,3 +17,33 @@ type CanStart interface {
 type CanStop interface {
 	StopService()
 }
+
+// CanBootContext is a context-aware variant of CanBoot, the returned error aborts startup.
+type CanBootContext interface {
+	BootService(ctx context.Context) error
+}
+
+// CanInitContext is a context-aware variant of CanInit, the returned error aborts startup.
+type CanInitContext interface {
+	InitService(ctx context.Context) error
+}
+
+// CanStartContext is a context-aware variant of CanStart, the returned error aborts startup
+// and stops services which are already started.
+type CanStartContext interface {
+	StartService(ctx context.Context) error
+}
+
+// CanStopContext is a context-aware variant of CanStop.
+type CanStopContext interface {
+	StopService(ctx context.Context) error
+}
+
+// Lifecycle drives container services through boot, init, start and stop phases.
+type Lifecycle interface {
+	BootServices(ctx context.Context) error
+	InitServices(ctx context.Context) error
+	StartServices(ctx context.Context) error
+	StopServices(ctx context.Context) error
+	Shutdown(ctx context.Context) error
+}
@@ -56,11 +56,13 @@ func (c *cronCommand) cronList(*cli.Context) error {
 }
 
 func (c *cronCommand) cronRun(ctx *cli.Context) error {
-	exit := make(chan os.Signal)
-	signal.Notify(exit, os.Interrupt, syscall.SIGTERM) //nolint:govet,staticcheck
+	exit := make(chan os.Signal, 1)
+	signal.Notify(exit, os.Interrupt, syscall.SIGTERM)
 
 	name := ctx.Args().First()
-	c.app.InitService()
+	if err := c.app.InitServices(ctx.Context); err != nil {
+		return err
+	}
 
 	done := make(chan bool, 1)
 	go func() {
@@ -28,7 +28,7 @@ type gronService struct {
 var _ Service = (*gronService)(nil)
 var _ contracts.CanBoot = (*gronService)(nil)
 var _ contracts.CanStart = (*gronService)(nil)
-var _ contracts.CanStop = (*gronService)(nil)
+var _ contracts.CanStopContext = (*gronService)(nil)
 
 //goland:noinspection SpellCheckingInspection
 func NewGronService(enabled bool, log logger.Logger) Service {
@@ -56,7 +56,6 @@ func (s *gronService) unsetCancelTask(taskId string) {
 }
 
 func (s *gronService) callCancelTasks() {
-	defer s.wg.Wait()
 	s.Lock()
 	defer s.Unlock()
 	for _, cancel := range s.cancelTasks {
@@ -111,9 +110,22 @@ func (s *gronService) StartService() {
 	}
 }
 
-func (s *gronService) StopService() {
+func (s *gronService) StopService(ctx context.Context) error {
 	s.cron.Stop()
 	s.callCancelTasks()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for running tasks: %w", ctx.Err())
+	}
 }
 
 func (s *gronService) Add(tasks ...Task) {
@@ -5,17 +5,25 @@ import (
 	"github.com/N-Vokhmyanin/go-framework/logger"
 )
 
+// CronProviderName is the name other providers depend on the cron provider by.
+const CronProviderName = "cron"
+
 type cronProvider struct {
 	enabled bool
 }
 
 var _ contracts.Provider = (*cronProvider)(nil)
+var _ contracts.ProviderWithName = (*cronProvider)(nil)
 
 //goland:noinspection GoUnusedExportedFunction
 func NewCronProvider() contracts.Provider {
 	return &cronProvider{}
 }
 
+func (p *cronProvider) ProviderName() string {
+	return CronProviderName
+}
+
 func (p *cronProvider) Config(c contracts.ConfigSet) {
 	c.BoolVar(&p.enabled, "CRON_ENABLED", false, "enable cron tasks")
 }
@@ -28,6 +36,7 @@ func (p *cronProvider) Boot(a contracts.Application) {
 
 func (p *cronProvider) Register(a contracts.Application) {
 	a.Make(func(service Service, log logger.Logger) {
+		service.Middleware(ScopeTaskMiddleware(a))
 		a.Command(NewCronCommands(a, service, log)...)
 	})
 }
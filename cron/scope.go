@@ -0,0 +1,21 @@
+package cron
+
+import (
+	"context"
+	"github.com/N-Vokhmyanin/go-framework/application/ctxapp"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/logger"
+)
+
+// ScopeTaskMiddleware resolves scoped bindings once per task run and disposes them when the run ends.
+func ScopeTaskMiddleware(c contracts.Container) Middleware {
+	return func(ctx context.Context, log logger.Logger, task Task) error {
+		scope := c.NewScope()
+		defer func() {
+			if err := scope.Close(); err != nil {
+				log.Errorw("close scope", "task", task.Name(), "error", err)
+			}
+		}()
+		return task.Handle(ctxapp.Inject(ctx, scope), log)
+	}
+}
@@ -21,8 +21,7 @@ func NewMigrateCommands(app contracts.Application, mig migorm.Migrater) []*cli.C
 	}
 
 	initService := func(ctx *cli.Context) error {
-		app.InitService()
-		return nil
+		return app.InitServices(ctx.Context)
 	}
 
 	return []*cli.Command{
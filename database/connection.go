@@ -3,12 +3,14 @@ package database
 import (
 	"context"
 	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/errors"
 	health "github.com/N-Vokhmyanin/go-framework/health/contracts"
 	"github.com/N-Vokhmyanin/go-framework/logger"
 	"go.uber.org/zap"
 	grpcHealthV1 "google.golang.org/grpc/health/grpc_health_v1"
 	"gorm.io/gorm"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -18,10 +20,11 @@ type gormConnection struct {
 	db        *gorm.DB
 	gormCfg   *gorm.Config
 	log       logger.Logger
-	connected bool
+	connected atomic.Bool
 	closed    bool
 
 	initFlag bool
+	watching bool
 	initLock sync.Mutex
 
 	callbacks []Callback
@@ -29,7 +32,7 @@ type gormConnection struct {
 
 var _ Connection = (*gormConnection)(nil)
 var _ health.Service = (*gormConnection)(nil)
-var _ contracts.CanStart = (*gormConnection)(nil)
+var _ contracts.CanStartContext = (*gormConnection)(nil)
 var _ contracts.CanStop = (*gormConnection)(nil)
 
 func NewGormConnection(dialector gorm.Dialector, gormCfg *gorm.Config, log logger.Logger) Connection {
@@ -47,14 +50,38 @@ func (c *gormConnection) DB() *gorm.DB {
 }
 
 func (c *gormConnection) Connect() {
+	_ = c.connect(context.Background())
+}
+
+// connect starts the connection watcher once and waits until connected or ctx is done,
+// after a timeout the next call waits for the connection again.
+func (c *gormConnection) connect(ctx context.Context) error {
 	c.initLock.Lock()
 	defer c.initLock.Unlock()
 
 	if c.initFlag {
-		return
+		return nil
+	}
+	if !c.watching {
+		c.watching = true
+		c.watch()
+	}
+
+	// waiting for gormConnection
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for !c.connected.Load() {
+		select {
+		case <-ctx.Done():
+			return errors.WrapWith(ctx.Err(), "waiting for connection")
+		case <-ticker.C:
+		}
 	}
 	c.initFlag = true
+	return nil
+}
 
+func (c *gormConnection) watch() {
 	connect := func() {
 		db, err := gorm.Open(c.dialector, c.gormCfg)
 		if err != nil {
@@ -62,7 +89,7 @@ func (c *gormConnection) Connect() {
 		} else {
 			c.runCallbacks(db)
 			c.db = db
-			c.connected = true
+			c.connected.Store(true)
 			c.log.Infow("connected successful")
 		}
 	}
@@ -71,12 +98,12 @@ func (c *gormConnection) Connect() {
 		sqlDB, err := c.db.DB()
 		if err != nil {
 			c.log.Errorw("get sql db failed", zap.Error(err))
-			c.connected = false
+			c.connected.Store(false)
 			connect()
 		}
 		if err = sqlDB.Ping(); err != nil {
 			c.log.Errorw("ping failed", zap.Error(err))
-			c.connected = false
+			c.connected.Store(false)
 			connect()
 		}
 	}
@@ -88,7 +115,7 @@ func (c *gormConnection) Connect() {
 		defer func() { _ = recover() }()
 
 		if !c.closed {
-			if !c.connected {
+			if !c.connected.Load() {
 				connect()
 			} else {
 				ping()
@@ -102,23 +129,13 @@ func (c *gormConnection) Connect() {
 			time.Sleep(5 * time.Second)
 		}
 	}()
-
-	// waiting for gormConnection
-	for {
-		if c.connected {
-			break
-		}
-
-		time.Sleep(100 * time.Millisecond)
-	}
-
 }
 
 func (c *gormConnection) Close() {
 	c.Lock()
 	defer c.Unlock()
 
-	if c.db != nil && c.connected {
+	if c.db != nil && c.connected.Load() {
 		sqlDB, err := c.db.DB()
 		if err == nil {
 			_ = sqlDB.Close()
@@ -128,7 +145,7 @@ func (c *gormConnection) Close() {
 }
 
 func (c *gormConnection) IsConnected() bool {
-	return c.connected
+	return c.connected.Load()
 }
 
 func (c *gormConnection) Register(cb Callback) {
@@ -136,7 +153,7 @@ func (c *gormConnection) Register(cb Callback) {
 	defer c.Unlock()
 
 	c.callbacks = append(c.callbacks, cb)
-	if c.connected && c.db != nil {
+	if c.connected.Load() && c.db != nil {
 		cb(c.db)
 	}
 }
@@ -150,11 +167,11 @@ func (c *gormConnection) runCallbacks(db *gorm.DB) {
 }
 
 func (c *gormConnection) HealthStatus(context.Context) grpcHealthV1.HealthCheckResponse_ServingStatus {
-	return health.HealthStatusFromBool(c.connected)
+	return health.HealthStatusFromBool(c.connected.Load())
 }
 
-func (c *gormConnection) StartService() {
-	c.Connect()
+func (c *gormConnection) StartService(ctx context.Context) error {
+	return c.connect(ctx)
 }
 
 func (c *gormConnection) StopService() {
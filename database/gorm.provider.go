@@ -1,19 +1,33 @@
 package database
 
 import (
+	"github.com/N-Vokhmyanin/go-framework/application/config"
 	"github.com/N-Vokhmyanin/go-framework/contracts"
 	"github.com/N-Vokhmyanin/go-framework/database/migorm"
+	"github.com/N-Vokhmyanin/go-framework/errors"
 	"github.com/N-Vokhmyanin/go-framework/logger"
+	"github.com/N-Vokhmyanin/go-framework/utils/di"
 	"gorm.io/gorm"
 	gormLogger "gorm.io/gorm/logger"
+	"sort"
 	"time"
 )
 
+const (
+	// GormProviderName is the name other providers depend on the gorm provider by.
+	GormProviderName = "database.gorm"
+
+	// defaultDBPass is an insecure default password, it is refused in production.
+	defaultDBPass = "password"
+)
+
 type gormProvider struct {
 	configs Configs
 }
 
 var _ contracts.Provider = (*gormProvider)(nil)
+var _ contracts.ProviderWithName = (*gormProvider)(nil)
+var _ contracts.ConfigValidator = (*gormProvider)(nil)
 
 //goland:noinspection GoUnusedExportedFunction
 func NewGormProvider(configs Configs) contracts.Provider {
@@ -22,24 +36,64 @@ func NewGormProvider(configs Configs) contracts.Provider {
 	}
 }
 
+func (p *gormProvider) ProviderName() string {
+	return GormProviderName
+}
+
 func (p *gormProvider) Config(c contracts.ConfigSet) {
 	for _, cfg := range p.configs {
 		c.StringVar(&cfg.DBHost, cfg.UpperPrefix()+"DB_HOST", "mysql", cfg.LowerPrefix()+"db host")
 		c.StringVar(&cfg.DBPort, cfg.UpperPrefix()+"DB_PORT", "3306", cfg.LowerPrefix()+"db port")
 		c.StringVar(&cfg.DBUser, cfg.UpperPrefix()+"DB_USER", "user", cfg.LowerPrefix()+"db username")
-		c.StringVar(&cfg.DBPass, cfg.UpperPrefix()+"DB_PASS", "password", cfg.LowerPrefix()+"db password")
+		config.SecretVar(c, &cfg.DBPass, cfg.UpperPrefix()+"DB_PASS", defaultDBPass, cfg.LowerPrefix()+"db password")
 		c.StringVar(&cfg.DBName, cfg.UpperPrefix()+"DB_NAME", "database", cfg.LowerPrefix()+"db name")
 
 		c.StringVar(&cfg.LogLevel, cfg.UpperPrefix()+"DB_LOG_LEVEL", "warn", "gorm log level")
 		c.DurationVar(&cfg.SlowThreshold, cfg.UpperPrefix()+"DB_SLOW_THRESHOLD", 200*time.Millisecond, "slow threshold duration")
 		c.BoolVar(&cfg.MigrationsRunOnStart, cfg.UpperPrefix()+"RUN_MIGRATIONS", false, "run migrations on start")
+
+		config.Rules(c, cfg.UpperPrefix()+"DB_PORT", config.Min(1), config.Max(65535))
+		config.Rules(c, cfg.UpperPrefix()+"DB_LOG_LEVEL", config.OneOf(gormLoggerLevelNames()...))
 	}
 }
 
+func gormLoggerLevelNames() []string {
+	names := make([]string, 0, len(GormLoggerLevels))
+	for name := range GormLoggerLevels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateConfig refuses the default passwords in production.
+func (p *gormProvider) ValidateConfig(env string) error {
+	if env != contracts.EnvProduction {
+		return nil
+	}
+	errs := errors.NewMultiError()
+	for _, cfg := range p.configs {
+		if cfg.DBPass == defaultDBPass {
+			errs.Append(errors.NewFieldValidationError(cfg.UpperPrefix()+"DB_PASS", "default password is not allowed in production"))
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
 func (p *gormProvider) Boot(a contracts.Application) {
 	a.Singleton(NewConnectionRegistry)
 	a.Singleton(func(r ConnectionRegistry) ConnectionPool { return r })
 
+	for _, cfg := range p.configs {
+		if cfg.IsDefault() {
+			continue
+		}
+		name := cfg.Name
+		a.SingletonNamed(name, func(r ConnectionRegistry) Connection {
+			return r.Get(name)
+		})
+	}
+
 	defaultCfg := p.configs.Default()
 	if defaultCfg != nil {
 		a.Singleton(func(r ConnectionRegistry) Connection {
@@ -91,7 +145,9 @@ func (p *gormProvider) Register(a contracts.Application) {
 		}
 	})
 
-	a.Make(func(mig migorm.Migrater) {
-		a.Command(NewMigrateCommands(a, mig)...)
+	a.Make(func(mig di.Optional[migorm.Migrater]) {
+		if mig.Ok() {
+			a.Command(NewMigrateCommands(a, mig.Value())...)
+		}
 	})
 }
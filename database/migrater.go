@@ -12,7 +12,7 @@ import (
 )
 
 var _ migorm.Migrater = (*migraterService)(nil)
-var _ contracts.CanStart = (*migraterService)(nil)
+var _ contracts.CanStartContext = (*migraterService)(nil)
 
 //goland:noinspection SpellCheckingInspection
 type migraterService struct {
@@ -87,12 +87,9 @@ func (m *migraterService) MakeFileMigration(ctx context.Context, name string) er
 	return m.migrater(false).MakeFileMigration(ctx, name)
 }
 
-func (m *migraterService) StartService() {
-	if m.upOnStart {
-		ctx := m.newContext(context.Background())
-		err := m.UpMigrations(ctx)
-		if err != nil {
-			panic(err)
-		}
+func (m *migraterService) StartService(ctx context.Context) error {
+	if !m.upOnStart {
+		return nil
 	}
+	return m.UpMigrations(m.newContext(ctx))
 }
@@ -0,0 +1,219 @@
+package broadcast
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/errors"
+	"github.com/N-Vokhmyanin/go-framework/logger"
+	"github.com/N-Vokhmyanin/go-framework/queue"
+	"reflect"
+	"sync"
+)
+
+// JobName is the name of jobs which carry broadcast events.
+const JobName = "broadcast.event"
+
+// Event is published to other services when Broadcast returns true.
+type Event interface {
+	Broadcast() bool
+}
+
+// UpgradeFunc converts the payload of an event version to the next version.
+type UpgradeFunc func(payload json.RawMessage) (json.RawMessage, error)
+
+type Broadcaster interface {
+	// Register publishes events of the type under the name and version, and dispatches received ones.
+	Register(typ reflect.Type, name string, version int)
+	// Upgrade adds the conversion of payloads of the named event from the version to the next one.
+	Upgrade(name string, from int, fn UpgradeFunc)
+}
+
+// Register registers the event type E with the broadcaster, the name must be the same in all services.
+//
+//goland:noinspection GoUnusedExportedFunction
+func Register[E Event](b Broadcaster, name string, version int) {
+	b.Register(reflect.TypeOf((*E)(nil)).Elem(), name, version)
+}
+
+type receivedKey struct{}
+
+// IsReceived reports whether the event being handled was received from another service.
+//
+//goland:noinspection GoUnusedExportedFunction
+func IsReceived(ctx context.Context) bool {
+	received, _ := ctx.Value(receivedKey{}).(bool)
+	return received
+}
+
+// envelope is the body of the job carrying an event.
+type envelope struct {
+	Name    string          `json:"name"`
+	Version int             `json:"version"`
+	Origin  string          `json:"origin"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+type eventType struct {
+	typ     reflect.Type
+	version int
+}
+
+// broadcaster publishes events to its queue bound to the topic, so every queue of the topic receives them.
+// The publishing queue does not receive its own events, as listeners of the publisher already handled them.
+// By default each instance has its own queue, so other instances of the service receive the events too,
+// instances sharing a configured queue share delivery.
+type broadcaster struct {
+	mu         sync.RWMutex
+	queue      string
+	manager    queue.Manager
+	dispatcher contracts.Dispatcher
+	log        logger.Logger
+	types      map[string]eventType
+	upgrades   map[string]map[int]UpgradeFunc
+}
+
+var _ Broadcaster = (*broadcaster)(nil)
+
+func NewBroadcaster(queueName string, manager queue.Manager, dispatcher contracts.Dispatcher, log logger.Logger) Broadcaster {
+	return &broadcaster{
+		queue:      queueName,
+		manager:    manager,
+		dispatcher: dispatcher,
+		log:        log.With(logger.WithComponent, "events.broadcast"),
+		types:      make(map[string]eventType),
+		upgrades:   make(map[string]map[int]UpgradeFunc),
+	}
+}
+
+func (b *broadcaster) Register(typ reflect.Type, name string, version int) {
+	b.mu.Lock()
+	if _, ok := b.types[name]; ok {
+		b.mu.Unlock()
+		b.log.Warnw("broadcast event already registered", "event.name", name)
+		return
+	}
+	b.types[name] = eventType{typ: typ, version: version}
+	b.mu.Unlock()
+
+	b.dispatcher.Subscribe(typ, contracts.EventListener{
+		Handle: func(ctx context.Context, e interface{}) error {
+			return b.publish(ctx, name, version, e)
+		},
+	})
+}
+
+func (b *broadcaster) Upgrade(name string, from int, fn UpgradeFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.upgrades[name] == nil {
+		b.upgrades[name] = make(map[int]UpgradeFunc)
+	}
+	b.upgrades[name][from] = fn
+}
+
+func (b *broadcaster) publish(ctx context.Context, name string, version int, e interface{}) error {
+	if IsReceived(ctx) {
+		return nil
+	}
+	if event, ok := e.(Event); !ok || !event.Broadcast() {
+		return nil
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return errors.WrapWith(err, "encode broadcast event "+name)
+	}
+	return b.manager.Push(ctx, &job{
+		queue: b.queue,
+		envelope: envelope{
+			Name:    name,
+			Version: version,
+			Origin:  b.queue,
+			Payload: payload,
+		},
+	})
+}
+
+// handler returns the handler dispatching events received by the queue.
+func (b *broadcaster) handler() queue.Handler {
+	return queue.SimpleHandler(
+		JobName,
+		b.queue,
+		func(ctx context.Context, log logger.Logger, i queue.JobInteract) error {
+			// envelopes which can not be decoded fail on every attempt, so they are not requeued
+			var env envelope
+			if err := i.Unmarshal(&env); err != nil {
+				return i.Fail(err)
+			}
+			if env.Origin == b.queue {
+				return nil
+			}
+			e, ok, err := b.decode(env)
+			if err != nil {
+				return i.Fail(err)
+			}
+			if !ok {
+				log.Debugw("broadcast event is not registered", "event.name", env.Name)
+				return nil
+			}
+			return b.dispatcher.Dispatch(context.WithValue(ctx, receivedKey{}, true), e)
+		},
+	)
+}
+
+// decode upgrades the payload to the registered version and decodes it, unregistered events are not decoded.
+func (b *broadcaster) decode(env envelope) (interface{}, bool, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	registered, ok := b.types[env.Name]
+	if !ok {
+		return nil, false, nil
+	}
+	if env.Version > registered.version {
+		return nil, true, fmt.Errorf(
+			"broadcast event %s version %d is newer than registered version %d",
+			env.Name, env.Version, registered.version,
+		)
+	}
+
+	payload := env.Payload
+	for version := env.Version; version < registered.version; version++ {
+		upgrade, found := b.upgrades[env.Name][version]
+		if !found {
+			return nil, true, fmt.Errorf("no upgrade of broadcast event %s from version %d", env.Name, version)
+		}
+		var err error
+		if payload, err = upgrade(payload); err != nil {
+			return nil, true, errors.WrapWith(err, fmt.Sprintf("upgrade broadcast event %s from version %d", env.Name, version))
+		}
+	}
+
+	e := reflect.New(registered.typ)
+	if err := json.Unmarshal(payload, e.Interface()); err != nil {
+		return nil, true, errors.WrapWith(err, "decode broadcast event "+env.Name)
+	}
+	return e.Elem().Interface(), true, nil
+}
+
+type job struct {
+	queue    string
+	envelope envelope
+}
+
+var _ queue.Job = (*job)(nil)
+
+func (j *job) Name() string {
+	return JobName
+}
+
+func (j *job) Queue() string {
+	return j.queue
+}
+
+func (j *job) Body() ([]byte, error) {
+	return json.Marshal(j.envelope)
+}
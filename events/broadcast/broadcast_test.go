@@ -0,0 +1,183 @@
+package broadcast
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/N-Vokhmyanin/go-framework/application"
+	"github.com/N-Vokhmyanin/go-framework/events"
+	"github.com/N-Vokhmyanin/go-framework/logger"
+	"github.com/N-Vokhmyanin/go-framework/queue"
+	"reflect"
+	"testing"
+)
+
+type orderPaid struct {
+	ID     int  `json:"id"`
+	Amount int  `json:"amount"`
+	Public bool `json:"-"`
+}
+
+func (e orderPaid) Broadcast() bool {
+	return e.Public
+}
+
+// testTopic delivers pushed jobs to handlers of all queues, like a fanout exchange.
+type testTopic struct {
+	queue.Manager
+	handlers []queue.Handler
+	pushed   int
+	failed   int
+}
+
+func (m *testTopic) Push(ctx context.Context, job queue.Job, _ ...queue.JobOptionFunc) error {
+	m.pushed++
+	body, err := job.Body()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, handler := range m.handlers {
+		errs = append(errs, handler.Handle(ctx, logger.GetNopLogger(), &testInteract{body: body, failed: &m.failed}))
+	}
+	return errors.Join(errs...)
+}
+
+type testInteract struct {
+	queue.JobInteract
+	body   []byte
+	failed *int
+}
+
+func (i *testInteract) Fail(err error) error {
+	*i.failed++
+	return err
+}
+
+func (i *testInteract) Unmarshal(j interface{}) error {
+	return json.Unmarshal(i.body, j)
+}
+
+func Test_broadcaster(t *testing.T) {
+	topic := &testTopic{}
+	newService := func(queueName string, version int) (*broadcaster, *[]orderPaid) {
+		d := application.NewDispatcher()
+		b := NewBroadcaster(queueName, topic, d, logger.GetNopLogger()).(*broadcaster)
+		Register[orderPaid](b, "order.paid", version)
+		topic.handlers = append(topic.handlers, b.handler())
+
+		var received []orderPaid
+		events.Listen(d, func(_ context.Context, e orderPaid) error {
+			received = append(received, e)
+			return nil
+		})
+		return b, &received
+	}
+
+	publisher, published := newService("billing.events.a", 1)
+	_, replicaReceived := newService("billing.events.b", 1)
+	_, received := newService("shipping.events", 2)
+	upgraded, upgradedReceived := newService("mailing.events", 2)
+	upgraded.Upgrade("order.paid", 1, func(payload json.RawMessage) (json.RawMessage, error) {
+		var v map[string]interface{}
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return nil, err
+		}
+		v["amount"] = v["amount"].(float64) * 100
+		return json.Marshal(v)
+	})
+
+	if err := events.Fire(context.Background(), publisher.dispatcher, orderPaid{ID: 1, Amount: 5}); err != nil || topic.pushed != 0 {
+		t.Errorf("Fire() of local event = %v, pushed %d, want no push", err, topic.pushed)
+	}
+	err := events.Fire(context.Background(), publisher.dispatcher, orderPaid{ID: 2, Amount: 5, Public: true})
+	if err == nil || topic.failed != 1 {
+		t.Errorf("Fire() of event without upgrade = %v, failed %d, want failed job", err, topic.failed)
+	}
+
+	if want := []orderPaid{{ID: 1, Amount: 5}, {ID: 2, Amount: 5, Public: true}}; !reflect.DeepEqual(*published, want) {
+		t.Errorf("publisher received %v, want %v", *published, want)
+	}
+	if want := []orderPaid{{ID: 2, Amount: 5}}; !reflect.DeepEqual(*replicaReceived, want) {
+		t.Errorf("other instance of the publisher received %v, want %v", *replicaReceived, want)
+	}
+	if want := []orderPaid{{ID: 2, Amount: 500}}; !reflect.DeepEqual(*upgradedReceived, want) {
+		t.Errorf("upgraded service received %v, want %v", *upgradedReceived, want)
+	}
+	if len(*received) != 0 {
+		t.Errorf("service without upgrade received %v, want none", *received)
+	}
+}
+
+func Test_broadcaster_handler(t *testing.T) {
+	d := application.NewDispatcher()
+	b := NewBroadcaster("shipping.events", nil, d, logger.GetNopLogger()).(*broadcaster)
+	Register[orderPaid](b, "order.paid", 1)
+	events.Listen(d, func(_ context.Context, e orderPaid) error {
+		if e.ID == 0 {
+			return errors.New("listener failed")
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name       string
+		body       string
+		wantErr    bool
+		wantFailed bool
+	}{
+		{name: "dispatched", body: `{"name":"order.paid","version":1,"payload":{"id":1}}`},
+		{name: "unregistered event", body: `{"name":"order.shipped","version":1,"payload":{}}`},
+		{name: "own event", body: `{"name":"order.paid","version":1,"origin":"shipping.events","payload":{}}`},
+		{name: "listener error is retried", body: `{"name":"order.paid","version":1,"payload":{"id":0}}`, wantErr: true},
+		{name: "invalid envelope", body: `[]`, wantErr: true, wantFailed: true},
+		{name: "newer version", body: `{"name":"order.paid","version":2,"payload":{"id":1}}`, wantErr: true, wantFailed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var failed int
+			err := b.handler().Handle(context.Background(), logger.GetNopLogger(), &testInteract{body: []byte(tt.body), failed: &failed})
+			if (err != nil) != tt.wantErr || (failed > 0) != tt.wantFailed {
+				t.Errorf("Handle() error = %v, failed = %d, want error %v, failed %v", err, failed, tt.wantErr, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func Test_broadcaster_decode(t *testing.T) {
+	b := NewBroadcaster("events", nil, application.NewDispatcher(), logger.GetNopLogger()).(*broadcaster)
+	Register[orderPaid](b, "order.paid", 1)
+
+	tests := []struct {
+		name    string
+		env     envelope
+		want    interface{}
+		ok      bool
+		wantErr bool
+	}{
+		{
+			name: "registered event",
+			env:  envelope{Name: "order.paid", Version: 1, Payload: json.RawMessage(`{"id":1}`)},
+			want: orderPaid{ID: 1},
+			ok:   true,
+		},
+		{
+			name: "unregistered event",
+			env:  envelope{Name: "order.shipped", Version: 1, Payload: json.RawMessage(`{}`)},
+		},
+		{
+			name:    "newer version",
+			env:     envelope{Name: "order.paid", Version: 2, Payload: json.RawMessage(`{}`)},
+			ok:      true,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := b.decode(tt.env)
+			if (err != nil) != tt.wantErr || ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decode() = %v, %v, %v, want %v, %v", got, ok, err, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package broadcast
+
+import (
+	"github.com/N-Vokhmyanin/go-framework/application/config"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/logger"
+	"github.com/N-Vokhmyanin/go-framework/queue"
+	"math/rand/v2"
+	"strconv"
+)
+
+type provider struct {
+	topic    string
+	queue    string
+	workers  uint
+	instance bool // the queue is of this instance only
+}
+
+var _ contracts.ProviderWithDependencies = (*provider)(nil)
+
+//goland:noinspection GoUnusedExportedFunction
+func NewProvider() contracts.Provider {
+	return &provider{}
+}
+
+func (p *provider) Config(c contracts.ConfigSet) {
+	c.StringVar(&p.topic, "BROADCAST_TOPIC", "events", "exchange broadcast events are published to")
+	c.StringVar(&p.queue, "BROADCAST_QUEUE", "", "durable queue receiving broadcast events shared by instances, by default each instance has its own auto-deleted queue")
+	c.UintVar(&p.workers, "BROADCAST_WORKERS", 1, "workers handling received broadcast events")
+	config.Rules(c, "BROADCAST_WORKERS", config.Min(1))
+}
+
+func (p *provider) DependsOn() []string {
+	return []string{
+		queue.AmqpProviderName,
+	}
+}
+
+func (p *provider) Boot(a contracts.Application) {
+	p.instance = p.queue == ""
+	if p.instance {
+		p.queue = a.Name() + ".events." + strconv.FormatUint(rand.Uint64(), 36)
+	}
+	a.Singleton(func(manager queue.Manager, dp contracts.Dispatcher, log logger.Logger) Broadcaster {
+		return NewBroadcaster(p.queue, manager, dp, log)
+	})
+}
+
+func (p *provider) Register(a contracts.Application) {
+	a.Make(func(b Broadcaster, manager queue.Manager) {
+		if p.instance {
+			manager.Queue(queue.AutoDeleteTopicQueue(p.queue, p.topic, p.workers))
+		} else {
+			manager.Queue(queue.TopicQueue(p.queue, p.topic, p.workers))
+		}
+		if bc, ok := b.(*broadcaster); ok {
+			manager.Handler(bc.handler())
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package events
+
+import (
+	"context"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"reflect"
+)
+
+type options struct {
+	priority int
+	async    bool
+}
+
+type Option func(o *options)
+
+// Priority orders listeners of the event, listeners with higher priority are called first.
+//
+//goland:noinspection GoUnusedExportedFunction
+func Priority(priority int) Option {
+	return func(o *options) {
+		o.priority = priority
+	}
+}
+
+// Async runs the listener on the dispatcher worker pool, its errors are logged instead of returned by Fire.
+//
+//goland:noinspection GoUnusedExportedFunction
+func Async() Option {
+	return func(o *options) {
+		o.async = true
+	}
+}
+
+// Listen adds the listener of events of type E, E is matched exactly, so it should be a concrete type.
+//
+//goland:noinspection GoUnusedExportedFunction
+func Listen[E any](d contracts.Dispatcher, fn func(ctx context.Context, e E) error, opts ...Option) contracts.Subscription {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return d.Subscribe(reflect.TypeOf((*E)(nil)).Elem(), contracts.EventListener{
+		Handle: func(ctx context.Context, e interface{}) error {
+			return fn(ctx, e.(E))
+		},
+		Priority: o.priority,
+		Async:    o.async,
+	})
+}
+
+// Fire calls listeners of the event and returns errors of synchronous listeners,
+// a failing or panicking listener does not stop the others.
+//
+//goland:noinspection GoUnusedExportedFunction
+func Fire[E any](ctx context.Context, d contracts.Dispatcher, e E) error {
+	return d.Dispatch(ctx, e)
+}
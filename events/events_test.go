@@ -0,0 +1,111 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"github.com/N-Vokhmyanin/go-framework/application"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/events"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type userCreated struct {
+	ID int
+}
+
+func Test_Fire(t *testing.T) {
+	d := application.NewDispatcher()
+	var calls []string
+	record := func(name string, err error) func(context.Context, userCreated) error {
+		return func(context.Context, userCreated) error {
+			calls = append(calls, name)
+			return err
+		}
+	}
+
+	events.Listen(d, record("default", nil))
+	events.Listen(d, record("high", nil), events.Priority(10))
+	failing := events.Listen(d, record("failing", errors.New("failed")), events.Priority(5))
+	events.Listen(d, func(context.Context, userCreated) error {
+		calls = append(calls, "panicking")
+		panic("listener panic")
+	})
+
+	err := events.Fire(context.Background(), d, userCreated{ID: 1})
+	if want := []string{"high", "failing", "default", "panicking"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("listener calls = %v, want %v", calls, want)
+	}
+	if multiErr, ok := err.(interface{ WrappedErrors() []error }); !ok || len(multiErr.WrappedErrors()) != 2 {
+		t.Errorf("events.Fire() error = %v, want failing and panicking listener errors", err)
+	}
+
+	calls = nil
+	failing.Unsubscribe()
+	failing.Unsubscribe()
+	_ = events.Fire(context.Background(), d, userCreated{ID: 2})
+	if want := []string{"high", "default", "panicking"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("listener calls after unsubscribe = %v, want %v", calls, want)
+	}
+}
+
+func Test_Fire_sameNamedTypes(t *testing.T) {
+	d := application.NewDispatcher()
+	var first, second int
+	func() {
+		type event struct{}
+		events.Listen(d, func(context.Context, event) error {
+			first++
+			return nil
+		})
+		_ = events.Fire(context.Background(), d, event{})
+	}()
+	func() {
+		type event struct{}
+		events.Listen(d, func(context.Context, event) error {
+			second++
+			return nil
+		})
+	}()
+
+	if first != 1 || second != 0 {
+		t.Errorf("listener calls = %d, %d, want 1, 0", first, second)
+	}
+}
+
+func Test_Fire_async(t *testing.T) {
+	d := application.NewDispatcher(application.DispatcherWorkersOption(2))
+	var running, maxRunning, done atomic.Int32
+	events.Listen(d, func(ctx context.Context, _ userCreated) error {
+		n := running.Add(1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		running.Add(-1)
+		done.Add(1)
+		return ctx.Err()
+	}, events.Async())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	for i := 0; i < 6; i++ {
+		if err := events.Fire(ctx, d, userCreated{ID: i}); err != nil {
+			t.Errorf("events.Fire() error = %v", err)
+		}
+	}
+	cancel()
+
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), time.Second)
+	defer stopCancel()
+	if err := d.(contracts.CanStopContext).StopService(stopCtx); err != nil {
+		t.Fatalf("dispatcher.StopService() error = %v", err)
+	}
+	if done.Load() != 6 || maxRunning.Load() > 2 {
+		t.Errorf("async listener calls = %d, max running = %d, want 6 calls on 2 workers", done.Load(), maxRunning.Load())
+	}
+}
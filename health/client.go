@@ -1,6 +1,7 @@
 package health
 
 import (
+	"context"
 	"github.com/N-Vokhmyanin/go-framework/contracts"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -13,7 +14,7 @@ type healthClient struct {
 	grpcHealthV1.HealthClient
 }
 
-var _ contracts.CanStart = (*healthClient)(nil)
+var _ contracts.CanStartContext = (*healthClient)(nil)
 var _ grpcHealthV1.HealthClient = (*healthClient)(nil)
 
 func NewHealthClient(addr string) grpcHealthV1.HealthClient {
@@ -25,10 +26,17 @@ func NewHealthClient(addr string) grpcHealthV1.HealthClient {
 	}
 }
 
-func (c *healthClient) StartService() {
+func (c *healthClient) StartService(context.Context) error {
+	return c.dial(c.address)
+}
+
+// dial connects the client to the address, the health server may listen on another address than configured.
+func (c *healthClient) dial(addr string) error {
+	c.address = addr
 	cc, err := grpc.NewClient(c.address, c.dialOptions...)
 	if err != nil {
-		panic(err)
+		return err
 	}
 	c.HealthClient = grpcHealthV1.NewHealthClient(cc)
+	return nil
 }
@@ -10,10 +10,15 @@ type Service interface {
 	HealthStatus(ctx context.Context) grpcHealthV1.HealthCheckResponse_ServingStatus
 }
 
+// Drainer is implemented by services which report NOT_SERVING once drained before shutdown.
+type Drainer interface {
+	Drain()
+}
+
 type Transport interface {
 	contracts.CanBoot
-	contracts.CanStart
-	contracts.CanStop
+	contracts.CanStartContext
+	contracts.CanStopContext
 }
 
 func HealthStatusFromBool(val bool) grpcHealthV1.HealthCheckResponse_ServingStatus {
@@ -2,6 +2,7 @@ package health
 
 import (
 	"fmt"
+	"github.com/N-Vokhmyanin/go-framework/application/config"
 	"github.com/N-Vokhmyanin/go-framework/contracts"
 	health "github.com/N-Vokhmyanin/go-framework/health/contracts"
 	"github.com/N-Vokhmyanin/go-framework/logger"
@@ -22,6 +23,8 @@ func NewProvider() contracts.Provider {
 func (p *provider) Config(c contracts.ConfigSet) {
 	c.UintVar(&p.portGrpc, "HEALTH_GRPC_PORT", 10860, "grpc listener port")
 	c.UintVar(&p.portHttp, "HEALTH_HTTP_PORT", 10861, "http listener port")
+	config.Rules(c, "HEALTH_GRPC_PORT", config.Max(65535))
+	config.Rules(c, "HEALTH_HTTP_PORT", config.Max(65535))
 }
 
 func (p *provider) Boot(a contracts.Application) {
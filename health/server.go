@@ -4,32 +4,42 @@ import (
 	"context"
 	"github.com/N-Vokhmyanin/go-framework/contracts"
 	health "github.com/N-Vokhmyanin/go-framework/health/contracts"
+	"github.com/N-Vokhmyanin/go-framework/utils/di"
 	grpcHealthV1 "google.golang.org/grpc/health/grpc_health_v1"
+	"sync/atomic"
 )
 
 type healthServer struct {
 	container contracts.Container
+	draining  *atomic.Bool
 }
 
 var _ grpcHealthV1.HealthServer = (*healthServer)(nil)
+var _ health.Drainer = (*healthServer)(nil)
 
 func NewHealthServer(container contracts.Container) grpcHealthV1.HealthServer {
 	return &healthServer{
 		container: container,
+		draining:  &atomic.Bool{},
 	}
 }
 
+func (s healthServer) Drain() {
+	s.draining.Store(true)
+}
+
 func (s healthServer) Check(ctx context.Context, _ *grpcHealthV1.HealthCheckRequest) (res *grpcHealthV1.HealthCheckResponse, err error) {
 	res = &grpcHealthV1.HealthCheckResponse{
 		Status: grpcHealthV1.HealthCheckResponse_SERVING,
 	}
-	instances := s.container.Instances()
-	for _, instance := range instances {
-		if service, ok := instance.(health.Service); ok {
-			res.Status = service.HealthStatus(ctx)
-			if res.Status != grpcHealthV1.HealthCheckResponse_SERVING {
-				return res, nil
-			}
+	if s.draining.Load() {
+		res.Status = grpcHealthV1.HealthCheckResponse_NOT_SERVING
+		return res, nil
+	}
+	for _, service := range di.All[health.Service](s.container) {
+		res.Status = service.HealthStatus(ctx)
+		if res.Status != grpcHealthV1.HealthCheckResponse_SERVING {
+			return res, nil
 		}
 	}
 	return res, nil
@@ -1,7 +1,9 @@
 package health
 
 import (
+	"context"
 	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/errors"
 	health "github.com/N-Vokhmyanin/go-framework/health/contracts"
 	"github.com/N-Vokhmyanin/go-framework/logger"
 	"github.com/N-Vokhmyanin/go-framework/transport"
@@ -13,6 +15,7 @@ import (
 type healthTransport struct {
 	grpcServer  transport.GrpcServer
 	httpGateway transport.HttpGateway
+	client      grpcHealthV1.HealthClient
 }
 
 var _ health.Transport = (*healthTransport)(nil)
@@ -37,11 +40,13 @@ func NewHealthTransport(
 		transport.WithServerMuxOptions(
 			runtime.WithHealthzEndpoint(client),
 		),
+		transport.WithGrpcServer(grpcServer),
 	)
 
 	return &healthTransport{
 		grpcServer:  grpcServer,
 		httpGateway: httpGateway,
+		client:      client,
 	}
 }
 
@@ -54,20 +59,38 @@ func (t healthTransport) BootService() {
 	}
 }
 
-func (t healthTransport) StartService() {
-	if grpcStart, ok := t.grpcServer.(contracts.CanStart); ok {
-		grpcStart.StartService()
+func (t healthTransport) StartService(ctx context.Context) error {
+	if grpcStart, ok := t.grpcServer.(contracts.CanStartContext); ok {
+		if err := grpcStart.StartService(ctx); err != nil {
+			return err
+		}
 	}
-	if httpStart, ok := t.httpGateway.(contracts.CanStart); ok {
-		httpStart.StartService()
+	if client, ok := t.client.(*healthClient); ok && client.address != t.grpcServer.Addr() {
+		if err := client.dial(t.grpcServer.Addr()); err != nil {
+			if grpcStop, ok := t.grpcServer.(contracts.CanStopContext); ok {
+				_ = grpcStop.StopService(ctx)
+			}
+			return err
+		}
 	}
+	if httpStart, ok := t.httpGateway.(contracts.CanStartContext); ok {
+		if err := httpStart.StartService(ctx); err != nil {
+			if grpcStop, ok := t.grpcServer.(contracts.CanStopContext); ok {
+				_ = grpcStop.StopService(ctx)
+			}
+			return err
+		}
+	}
+	return nil
 }
 
-func (t healthTransport) StopService() {
-	if grpcStop, ok := t.grpcServer.(contracts.CanStop); ok {
-		grpcStop.StopService()
+func (t healthTransport) StopService(ctx context.Context) error {
+	errs := errors.NewMultiError()
+	if httpStop, ok := t.httpGateway.(contracts.CanStopContext); ok {
+		errs.Append(httpStop.StopService(ctx))
 	}
-	if httpStop, ok := t.httpGateway.(contracts.CanStop); ok {
-		httpStop.StopService()
+	if grpcStop, ok := t.grpcServer.(contracts.CanStopContext); ok {
+		errs.Append(grpcStop.StopService(ctx))
 	}
+	return errs.ErrorOrNil()
 }
@@ -59,13 +59,11 @@ func (p *provider) getZapLogger(isDev bool, inCfg *zap.Config) *zap.Logger {
 		cfg = mergeConfig(cfg, *loadedConfig)
 	}
 
-	if p.level != "" {
-		var lvl zapcore.Level
-		err := lvl.Set(p.level)
-		if err == nil {
-			cfg.Level = zap.NewAtomicLevelAt(lvl)
-		}
-	}
+	// the level is changed when LOG_LEVEL is reloaded
+	p.baseLevel = cfg.Level.Level()
+	p.atomicLevel = zap.NewAtomicLevelAt(p.baseLevel)
+	p.setLevel(p.level)
+	cfg.Level = p.atomicLevel
 
 	cfg.Development = isDev
 	cfg.Encoding = "json"
@@ -80,6 +78,17 @@ func (p *provider) getZapLogger(isDev bool, inCfg *zap.Config) *zap.Logger {
 	return p.zap
 }
 
+// setLevel changes the min level of the logger, an empty or invalid level restores the configured one.
+func (p *provider) setLevel(level string) {
+	lvl := p.baseLevel
+	if level != "" {
+		if err := lvl.Set(level); err != nil {
+			lvl = p.baseLevel
+		}
+	}
+	p.atomicLevel.SetLevel(lvl)
+}
+
 func (p *provider) loadConfig() (*zap.Config, error) {
 	if p.cfgFile == "" {
 		return nil, nil
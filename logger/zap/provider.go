@@ -1,9 +1,12 @@
 package zap_log
 
 import (
+	configRules "github.com/N-Vokhmyanin/go-framework/application/config"
 	"github.com/N-Vokhmyanin/go-framework/contracts"
 	"github.com/N-Vokhmyanin/go-framework/logger"
+	"github.com/N-Vokhmyanin/go-framework/utils/di"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 var emptyLevel = zap.AtomicLevel{}
@@ -18,6 +21,9 @@ type provider struct {
 
 	config *zap.Config
 
+	baseLevel   zapcore.Level
+	atomicLevel zap.AtomicLevel
+
 	zap *zap.Logger
 	log logger.Logger
 }
@@ -35,14 +41,15 @@ func (p *provider) WithConfig(cfg *zap.Config) *provider {
 
 func (p *provider) Config(c contracts.ConfigSet) {
 	c.StringVar(&p.level, "LOG_LEVEL", "", "min log level")
+	configRules.Rules(c, "LOG_LEVEL", configRules.OneOf("debug", "info", "warn", "error", "dpanic", "panic", "fatal"))
 	c.StringVar(&p.cfgFile, "LOG_CONFIG_FILE", "", "log config file")
 }
 
 func (p *provider) Boot(a contracts.Application) {
 	a.Singleton(
-		func(cfg *zap.Config) *zap.Logger {
-			isDev := a.Env() != contracts.EnvProduction
-			return p.getZapLogger(isDev, cfg)
+		func(cfg di.Optional[*zap.Config]) *zap.Logger {
+			isDev := !a.IsProduction()
+			return p.getZapLogger(isDev, cfg.Value())
 		},
 	)
 	a.Singleton(
@@ -53,6 +60,8 @@ func (p *provider) Boot(a contracts.Application) {
 	)
 }
 
-func (p *provider) Register(contracts.Application) {
-	// nothing
+func (p *provider) Register(a contracts.Application) {
+	a.Make(func(cfg contracts.Config, _ *zap.Logger) {
+		cfg.Subscribe("LOG_LEVEL", p.setLevel)
+	})
 }
@@ -7,7 +7,9 @@ import (
 	"github.com/N-Vokhmyanin/go-framework/errors"
 	health "github.com/N-Vokhmyanin/go-framework/health/contracts"
 	"github.com/N-Vokhmyanin/go-framework/logger"
+	"go.uber.org/zap"
 	grpcHealthV1 "google.golang.org/grpc/health/grpc_health_v1"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -21,13 +23,18 @@ type amqpManager struct {
 	connectors  map[string]*amqpConnector
 	middlewares []Middleware
 
+	// cfg scales queues when their workers config values are reloaded,
+	// workersConfigs holds config names of queues registered before it is set
+	cfg            contracts.Config
+	workersConfigs map[string]string
+
 	stoppingTimeout time.Duration
 }
 
 var _ Manager = (*amqpManager)(nil)
 var _ health.Service = (*amqpManager)(nil)
 var _ contracts.CanStart = (*amqpManager)(nil)
-var _ contracts.CanStop = (*amqpManager)(nil)
+var _ contracts.CanStopContext = (*amqpManager)(nil)
 
 func NewAmqpManager(
 	uri string,
@@ -43,6 +50,8 @@ func NewAmqpManager(
 		log:        log.With(logger.WithComponent, "queue.amqp"),
 		connectors: make(map[string]*amqpConnector),
 
+		workersConfigs: make(map[string]string),
+
 		stoppingTimeout: stoppingTimeout,
 	}
 }
@@ -56,8 +65,53 @@ func (s *amqpManager) Queue(queues ...Queue) {
 			s.log.Warnw("queue already registered", "name", queue.Name())
 		} else {
 			s.connectors[queue.Name()] = NewAmqpConnector(s.uri, s, queue, s.log, s.dp, s.cache, s.stoppingTimeout)
+			if wq, ok := queue.(QueueWithWorkersConfig); ok && wq.WorkersConfig() != "" {
+				s.workersConfigs[queue.Name()] = wq.WorkersConfig()
+			}
 		}
 	}
+	s.subscribeWorkers()
+}
+
+// watchWorkers scales queues with workers config values when the values are reloaded.
+func (s *amqpManager) watchWorkers(cfg contracts.Config) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.cfg = cfg
+	s.subscribeWorkers()
+}
+
+// subscribeWorkers subscribes to workers config values of registered queues, must be called with the lock held.
+func (s *amqpManager) subscribeWorkers() {
+	if s.cfg == nil {
+		return
+	}
+	for queueName, configName := range s.workersConfigs {
+		s.cfg.Subscribe(configName, func(value string) {
+			workers, err := strconv.ParseUint(value, 10, 0)
+			if err != nil {
+				s.log.Errorw("invalid queue workers count", "queue.name", queueName, "config.name", configName, zap.Error(err))
+				return
+			}
+			if err = s.Scale(queueName, uint(workers)); err != nil {
+				s.log.Errorw("failed to scale queue", "queue.name", queueName, zap.Error(err))
+			}
+		})
+	}
+	clear(s.workersConfigs)
+}
+
+func (s *amqpManager) Scale(queueName string, workers uint) error {
+	s.Lock()
+	queueConnector, ok := s.connectors[queueName]
+	s.Unlock()
+	if !ok {
+		return errors.Errorf("queue '%s' not registered", queueName)
+	}
+
+	queueConnector.Scale(workers)
+	return nil
 }
 
 func (s *amqpManager) MessagesCount(queueName string) (uint, error) {
@@ -145,8 +199,26 @@ func (s *amqpManager) StartService() {
 	}
 }
 
-func (s *amqpManager) StopService() {
+func (s *amqpManager) StopService(ctx context.Context) error {
+	var wg sync.WaitGroup
 	for _, queue := range s.connectors {
-		queue.StopService()
+		wg.Add(1)
+		go func(q *amqpConnector) {
+			defer wg.Done()
+			q.StopService()
+		}(queue)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.WrapWith(ctx.Err(), "waiting for queue workers")
 	}
 }
@@ -13,9 +13,10 @@ type jobHandlerFunc = func(ctx context.Context, log logger.Logger, i JobInteract
 type simpleQueue struct {
 	name    string
 	workers uint
+	config  string // name of the workers config value
 }
 
-var _ Queue = (*simpleQueue)(nil)
+var _ QueueWithWorkersConfig = (*simpleQueue)(nil)
 
 //goland:noinspection GoUnusedExportedFunction
 func SimpleQueue(name string, workers uint) Queue {
@@ -33,6 +34,56 @@ func (q *simpleQueue) Workers() uint {
 	return q.workers
 }
 
+// WorkersConfig returns the name of the workers config value, empty until SimpleQueues.Config is called.
+func (q *simpleQueue) WorkersConfig() string {
+	return q.config
+}
+
+type topicQueue struct {
+	simpleQueue
+	topic      string
+	autoDelete bool
+}
+
+var _ QueueWithTopic = (*topicQueue)(nil)
+var _ QueueWithAutoDelete = (*topicQueue)(nil)
+
+// TopicQueue creates a queue bound to the topic, jobs pushed to any queue of the topic are received by all of them.
+//
+//goland:noinspection GoUnusedExportedFunction
+func TopicQueue(name, topic string, workers uint) Queue {
+	return &topicQueue{
+		simpleQueue: simpleQueue{
+			name:    name,
+			workers: workers,
+		},
+		topic: topic,
+	}
+}
+
+// AutoDeleteTopicQueue creates a topic queue which lives while the application consumes it,
+// a uniquely named one receives the topic jobs for a single application instance.
+//
+//goland:noinspection GoUnusedExportedFunction
+func AutoDeleteTopicQueue(name, topic string, workers uint) Queue {
+	return &topicQueue{
+		simpleQueue: simpleQueue{
+			name:    name,
+			workers: workers,
+		},
+		topic:      topic,
+		autoDelete: true,
+	}
+}
+
+func (q *topicQueue) Topic() string {
+	return q.topic
+}
+
+func (q *topicQueue) AutoDelete() bool {
+	return q.autoDelete
+}
+
 type simpleHandler struct {
 	name  string
 	queue string
@@ -96,9 +147,10 @@ func (queues simpleQueues) Config(c contracts.ConfigSet, prefix string) {
 			}
 			name = strings.Replace(name, prefix, "", 1)
 		}
+		queue.config = fmt.Sprintf("QUEUE_%s_WORKERS", strings.ToUpper(strings.ReplaceAll(name, "-", "_")))
 		c.UintVar(
 			&queue.workers,
-			fmt.Sprintf("QUEUE_%s_WORKERS", strings.ToUpper(strings.ReplaceAll(name, "-", "_"))),
+			queue.config,
 			queue.workers,
 			fmt.Sprintf("workers for queue %s", name),
 		)
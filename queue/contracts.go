@@ -10,6 +10,26 @@ type Queue interface {
 	Workers() uint
 }
 
+// QueueWithTopic is a queue bound to a fanout exchange named by the topic,
+// jobs pushed to it are published to every queue bound to the topic.
+type QueueWithTopic interface {
+	Queue
+	Topic() string
+}
+
+// QueueWithAutoDelete is a non-durable queue the broker deletes once its last consumer is gone.
+type QueueWithAutoDelete interface {
+	Queue
+	AutoDelete() bool
+}
+
+// QueueWithWorkersConfig is a queue whose workers count is read from the named config value,
+// the manager scales the queue when the value is reloaded.
+type QueueWithWorkersConfig interface {
+	Queue
+	WorkersConfig() string
+}
+
 type Job interface {
 	Name() string
 	Queue() string
@@ -49,6 +69,8 @@ type Manager interface {
 	Connection
 	Queue(queues ...Queue)
 	MessagesCount(queueName string) (uint, error)
+	// Scale changes the number of the queue workers, retired workers finish their jobs in progress.
+	Scale(queueName string, workers uint) error
 	Middleware(middlewares ...Middleware)
 	GetMiddlewares() []Middleware
 }
@@ -24,6 +24,16 @@ func (e ErrUnknownJob) Error() string {
 	return fmt.Sprintf("unknown job: %s", e.Name)
 }
 
+// ErrUnsupportedOption is returned when a job option can not be honoured by the queue.
+type ErrUnsupportedOption struct {
+	Queue  string
+	Option string
+}
+
+func (e ErrUnsupportedOption) Error() string {
+	return fmt.Sprintf("queue %s does not support %s jobs", e.Queue, e.Option)
+}
+
 type ErrUnknownQueue struct {
 	Name string
 }
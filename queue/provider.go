@@ -2,9 +2,12 @@ package queue
 
 import (
 	"fmt"
+	"github.com/N-Vokhmyanin/go-framework/application/config"
 	"github.com/N-Vokhmyanin/go-framework/cache"
 	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/errors"
 	"github.com/N-Vokhmyanin/go-framework/logger"
+	"github.com/N-Vokhmyanin/go-framework/utils/di"
 	"time"
 )
 
@@ -17,31 +20,59 @@ type amqpProvider struct {
 	stoppingTimeout time.Duration
 }
 
+const (
+	// AmqpProviderName is the name other providers depend on the amqp provider by.
+	AmqpProviderName = "queue.amqp"
+
+	// defaultAmqpPass is an insecure default password, it is refused in production.
+	defaultAmqpPass = "password"
+)
+
 var _ contracts.Provider = (*amqpProvider)(nil)
+var _ contracts.ProviderWithName = (*amqpProvider)(nil)
+var _ contracts.ConfigValidator = (*amqpProvider)(nil)
 
 //goland:noinspection GoUnusedExportedFunction
 func NewAmqpProvider() contracts.Provider {
 	return &amqpProvider{}
 }
 
+func (p *amqpProvider) ProviderName() string {
+	return AmqpProviderName
+}
+
 func (p *amqpProvider) Config(c contracts.ConfigSet) {
 	c.StringVar(&p.host, "AMQP_HOST", "rabbitmq", "rabbitmq host")
 	c.StringVar(&p.port, "AMQP_PORT", "5672", "rabbitmq port")
 	c.StringVar(&p.user, "AMQP_USER", "user", "rabbitmq user")
-	c.StringVar(&p.pass, "AMQP_PASS", "password", "rabbitmq password")
+	config.SecretVar(c, &p.pass, "AMQP_PASS", defaultAmqpPass, "rabbitmq password")
+	config.Rules(c, "AMQP_PORT", config.Min(1), config.Max(65535))
 
 	c.DurationVar(&p.stoppingTimeout, "QUEUE_WORKER_STOPPING_TIMEOUT", time.Minute, "worker stopping timeout")
 }
 
+// ValidateConfig refuses the default password in production.
+func (p *amqpProvider) ValidateConfig(env string) error {
+	if env == contracts.EnvProduction && p.pass == defaultAmqpPass {
+		return errors.NewFieldValidationError("AMQP_PASS", "default password is not allowed in production")
+	}
+	return nil
+}
+
 func (p *amqpProvider) Boot(a contracts.Application) {
 	a.Singleton(
-		func(log logger.Logger, dp contracts.Dispatcher, ch cache.CacheInterface) Manager {
+		func(log logger.Logger, dp contracts.Dispatcher, ch di.Optional[cache.CacheInterface]) Manager {
 			uri := fmt.Sprintf("amqp://%s:%s@%s:%s/", p.user, p.pass, p.host, p.port)
-			return NewAmqpManager(uri, log, dp, ch, p.stoppingTimeout)
+			return NewAmqpManager(uri, log, dp, ch.Value(), p.stoppingTimeout)
 		},
 	)
 }
 
-func (p *amqpProvider) Register(contracts.Application) {
-	// nothing to register
+func (p *amqpProvider) Register(a contracts.Application) {
+	a.Make(func(manager Manager, cfg contracts.Config) {
+		manager.Middleware(ScopeHandlerMiddleware(a))
+		if m, ok := manager.(*amqpManager); ok {
+			m.watchWorkers(cfg)
+		}
+	})
 }
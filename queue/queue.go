@@ -33,6 +33,8 @@ type amqpConnector struct {
 	manager     Manager
 	uri         string
 	name        string
+	topic       string
+	autoDelete  bool
 	log         logger.Logger
 	dp          contracts.Dispatcher
 	cache       cache.CacheInterface
@@ -44,6 +46,7 @@ type amqpConnector struct {
 	notifyClose chan *amqp.Error
 	isConnected bool
 	isStopped   bool
+	isStarted   bool
 
 	stoppingTimeout time.Duration
 }
@@ -75,6 +78,12 @@ func NewAmqpConnector(
 
 		stoppingTimeout: stoppingTimeout,
 	}
+	if tq, ok := q.(QueueWithTopic); ok {
+		queue.topic = tq.Topic()
+	}
+	if aq, ok := q.(QueueWithAutoDelete); ok {
+		queue.autoDelete = aq.AutoDelete()
+	}
 	var i uint
 	for i = 0; i < q.Workers(); i++ {
 		queue.newWorker(fmt.Sprintf("worker-%d", i+1))
@@ -114,6 +123,16 @@ func (q *amqpConnector) Push(ctx context.Context, job Job, opts ...JobOptionFunc
 	} else if wrapper, err = wrap(WithOptions(job, opts...)); err != nil {
 		return err
 	}
+	if q.topic != "" {
+		// delays and once jobs would be delivered to this queue only
+		if wrapper.Options.Hash != "" {
+			return ErrUnsupportedOption{Queue: q.name, Option: "once"}
+		}
+		if wrapper.Options.GetDelay() > 0 {
+			return ErrUnsupportedOption{Queue: q.name, Option: "delayed"}
+		}
+		return q.publish(ctx, q.topic, "", wrapper)
+	}
 	if wrapper.Options.Hash != "" {
 		return q.once(ctx, wrapper, wrapper.Options.Hash)
 	}
@@ -127,20 +146,59 @@ func (q *amqpConnector) StartService() {
 	if !q.initConnection() {
 		return
 	}
+	q.Lock()
+	defer q.Unlock()
+	q.isStarted = true
 	for _, worker := range q.workers {
 		go worker.start()
 	}
 }
 
+// Scale starts or retires workers to match the count, retired workers finish their jobs in progress.
+func (q *amqpConnector) Scale(workers uint) {
+	q.Lock()
+	var retired []*amqpWorker
+	for i := uint(len(q.workers)); i < workers; i++ {
+		worker := q.addWorker(fmt.Sprintf("worker-%d", i+1))
+		if q.isStarted {
+			go worker.start()
+		}
+	}
+	for i := uint(len(q.workers)); i > workers; i-- {
+		name := fmt.Sprintf("worker-%d", i)
+		retired = append(retired, q.workers[name])
+		delete(q.workers, name)
+	}
+	q.Unlock()
+
+	q.log.Infow("workers scaled", "workers", workers)
+	var wg sync.WaitGroup
+	wg.Add(len(retired))
+	for _, worker := range retired {
+		go func(w *amqpWorker) {
+			defer wg.Done()
+			w.retire()
+		}(worker)
+	}
+	wg.Wait()
+}
+
 func (q *amqpConnector) StopService() {
 	close(q.notifyStop)
 	if !q.isConnected {
 		return
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(len(q.workers))
+	q.Lock()
+	workers := make([]*amqpWorker, 0, len(q.workers))
 	for _, worker := range q.workers {
+		workers = append(workers, worker)
+	}
+	q.Unlock()
+
+	var wg sync.WaitGroup
+	wg.Add(len(workers))
+	for _, worker := range workers {
 		go func(w *amqpWorker) {
 			defer wg.Done()
 			w.stop()
@@ -156,22 +214,27 @@ func (q *amqpConnector) StopService() {
 	q.isConnected = false
 }
 
-func (q *amqpConnector) stream() (<-chan amqp.Delivery, error) {
+func (q *amqpConnector) stream(consumer string) (<-chan amqp.Delivery, error) {
 	if !q.isConnected {
 		return nil, ErrNotConnected{}
 	}
 	return q.channel.Consume(
 		q.name,
-		"",    // Consumer
-		false, // Auto-Ack
-		false, // Exclusive
-		false, // No-local
-		false, // No-Wait
-		nil,   // Args
+		consumer, // Consumer
+		false,    // Auto-Ack
+		false,    // Exclusive
+		false,    // No-local
+		false,    // No-Wait
+		nil,      // Args
 	)
 }
 
-func (q *amqpConnector) push(ctx context.Context, job amqpJobWrapper) (err error) {
+func (q *amqpConnector) push(ctx context.Context, job amqpJobWrapper) error {
+	return q.publish(ctx, "", q.name, job)
+}
+
+// publish sends the job to the exchange, the default exchange routes it to the queue named by the key.
+func (q *amqpConnector) publish(ctx context.Context, exchange, key string, job amqpJobWrapper) (err error) {
 	if !q.isConnected {
 		return ErrNotConnected{}
 	}
@@ -190,10 +253,10 @@ func (q *amqpConnector) push(ctx context.Context, job amqpJobWrapper) (err error
 	}()
 
 	return q.channel.Publish(
-		"",     // Exchange
-		q.name, // Routing key
-		false,  // Mandatory
-		false,  // Immediate
+		exchange, // Exchange
+		key,      // Routing key
+		false,    // Mandatory
+		false,    // Immediate
 		amqp.Publishing{
 			ContentType: "application/json",
 			Timestamp:   time.Now(),
@@ -301,9 +364,20 @@ func (q *amqpConnector) delay(ctx context.Context, job amqpJobWrapper, delay tim
 	)
 }
 
+func (q *amqpConnector) cancel(consumer string) error {
+	if !q.isConnected {
+		return nil
+	}
+	return q.channel.Cancel(consumer, false)
+}
+
 func (q *amqpConnector) newWorker(name string) *amqpWorker {
 	q.Lock()
 	defer q.Unlock()
+	return q.addWorker(name)
+}
+
+func (q *amqpConnector) addWorker(name string) *amqpWorker {
 	worker := newAmqpWorker(name, q, q.log, q.dp, q.cache)
 	q.workers[name] = worker
 	return worker
@@ -326,17 +400,40 @@ func (q *amqpConnector) connect(addr string) error {
 	}
 
 	_, err = ch.QueueDeclare(
-		q.name, // Name
-		true,   // Durable
-		false,  // Delete when unused
-		false,  // Exclusive
-		false,  // No-wait
-		nil,    // Arguments
+		q.name,        // Name
+		!q.autoDelete, // Durable
+		q.autoDelete,  // Delete when unused
+		false,         // Exclusive
+		false,         // No-wait
+		nil,           // Arguments
 	)
 	if err != nil {
 		return err
 	}
 
+	if q.topic != "" {
+		if err = ch.ExchangeDeclare(
+			q.topic,  // Name
+			"fanout", // Kind
+			true,     // Durable
+			false,    // Auto-deleted
+			false,    // Internal
+			false,    // No-wait
+			nil,      // Arguments
+		); err != nil {
+			return err
+		}
+		if err = ch.QueueBind(
+			q.name,  // Name
+			"",      // Key
+			q.topic, // Exchange
+			false,   // No-wait
+			nil,     // Arguments
+		); err != nil {
+			return err
+		}
+	}
+
 	err = ch.Qos(
 		1,     // prefetch count
 		0,     // prefetch size
@@ -0,0 +1,21 @@
+package queue
+
+import (
+	"context"
+	"github.com/N-Vokhmyanin/go-framework/application/ctxapp"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/logger"
+)
+
+// ScopeHandlerMiddleware resolves scoped bindings once per job and disposes them when the job is handled.
+func ScopeHandlerMiddleware(c contracts.Container) Middleware {
+	return func(ctx context.Context, log logger.Logger, i JobInteract, handler Handler) error {
+		scope := c.NewScope()
+		defer func() {
+			if err := scope.Close(); err != nil {
+				log.Errorw("close scope", "handler", handler.Name(), "error", err)
+			}
+		}()
+		return handler.Handle(ctxapp.Inject(ctx, scope), log, i)
+	}
+}
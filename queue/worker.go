@@ -15,6 +15,7 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -22,6 +23,7 @@ const DefaultReleaseDelay = time.Second * 10
 
 type amqpWorker struct {
 	sync.Mutex
+	name  string
 	conn  *amqpConnector
 	log   logger.Logger
 	dp    contracts.Dispatcher
@@ -29,6 +31,7 @@ type amqpWorker struct {
 	cache cache.CacheInterface
 
 	cancelFunc context.CancelFunc
+	retired    atomic.Bool
 }
 
 func newAmqpWorker(
@@ -39,6 +42,7 @@ func newAmqpWorker(
 	ch cache.CacheInterface,
 ) *amqpWorker {
 	return &amqpWorker{
+		name:  name,
 		conn:  c,
 		log:   log.With("queue.worker", name),
 		dp:    dp,
@@ -53,7 +57,10 @@ func (w *amqpWorker) start() {
 	var delivery <-chan amqp.Delivery
 	for {
 		if isClosed {
-			delivery, err = w.conn.stream()
+			if w.retired.Load() {
+				return
+			}
+			delivery, err = w.conn.stream(w.consumer())
 			if err != nil {
 				w.log.Warnw("queue unavailable, waiting reconnect")
 				time.Sleep(10 * time.Second)
@@ -65,6 +72,13 @@ func (w *amqpWorker) start() {
 		if w.conn.isStopped {
 			return
 		}
+		if w.retired.Load() {
+			if ok {
+				// the message is returned to the queue for other workers
+				_ = msg.Nack(false, true)
+			}
+			return
+		}
 		if !ok {
 			isClosed = true
 			continue
@@ -73,6 +87,20 @@ func (w *amqpWorker) start() {
 	}
 }
 
+// retire stops consuming and waits for the job in progress, the connection stays open for other workers.
+func (w *amqpWorker) retire() {
+	w.retired.Store(true)
+	if err := w.conn.cancel(w.consumer()); err != nil {
+		w.log.Warnw("cancel consumer failed", zap.Error(err))
+	}
+	w.stop()
+	w.log.Infow("worker retired")
+}
+
+func (w *amqpWorker) consumer() string {
+	return w.conn.name + "." + w.name
+}
+
 func (w *amqpWorker) stop() {
 	wait := make(chan bool)
 	go func() {
@@ -1,6 +1,7 @@
 package queue
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -76,6 +77,28 @@ func wrap(job Job) (wrapper amqpJobWrapper, err error) {
 	return wrapper, nil
 }
 
+// Encode serializes the job with its options, so it can be stored and pushed later.
+//
+//goland:noinspection GoUnusedExportedFunction
+func Encode(job Job, opts ...JobOptionFunc) ([]byte, error) {
+	wrapper, err := wrap(WithOptions(job, opts...))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(wrapper)
+}
+
+// Decode restores the job encoded by Encode, pushing it keeps the encoded options.
+//
+//goland:noinspection GoUnusedExportedFunction
+func Decode(data []byte) (Job, error) {
+	wrapper := &amqpJobWrapper{}
+	if err := json.Unmarshal(data, wrapper); err != nil {
+		return nil, err
+	}
+	return wrapper, nil
+}
+
 func wrapSlice(jobs []Job) (wrappers []amqpJobWrapper, err error) {
 	for _, job := range jobs {
 		wrapper, wrapErr := wrap(job)
@@ -15,19 +15,26 @@ import (
 )
 
 type Config struct {
-	Enabled    bool
-	Endpoint   string
-	SampleRate float64
+	Enabled    bool    `env:"TRACER_ENABLED" default:"false" usage:"traces enabled"`
+	Endpoint   string  `env:"TRACER_ENDPOINT" default:"opentelemetry:4317" usage:"opentelemetry grpc port"`
+	SampleRate float64 `env:"TRACER_SAMPLE_RATE" default:"1.0" min:"0" max:"1" usage:"traces sample rate"`
+}
+
+// SampleRateSetter is implemented by tracers which sample rate can be changed at runtime.
+type SampleRateSetter interface {
+	SetSampleRate(rate float64)
 }
 
 type openTelemetryTracer struct {
 	traceExporter *otlptrace.Exporter
 	traceProvider trace.TracerProvider
+	sampler       *ratioSampler
 }
 
 var _ trace.Tracer = (*openTelemetryTracer)(nil)
-var _ contracts.CanInit = (*openTelemetryTracer)(nil)
-var _ contracts.CanStop = (*openTelemetryTracer)(nil)
+var _ SampleRateSetter = (*openTelemetryTracer)(nil)
+var _ contracts.CanInitContext = (*openTelemetryTracer)(nil)
+var _ contracts.CanStopContext = (*openTelemetryTracer)(nil)
 
 func NewOpenTelemetryTracer(serviceName string, cfg *Config) trace.Tracer {
 	opts := []otlptracegrpc.Option{
@@ -49,15 +56,15 @@ func NewOpenTelemetryTracer(serviceName string, cfg *Config) trace.Tracer {
 		panic(err)
 	}
 
+	sampler := newRatioSampler(cfg.SampleRate)
+
 	var traceProvider trace.TracerProvider
 	if cfg.Enabled {
 		traceProvider = sdktrace.NewTracerProvider(
 			sdktrace.WithBatcher(traceExporter),
 			sdktrace.WithResource(traceResource),
 			sdktrace.WithSampler(
-				sdktrace.ParentBased(
-					sdktrace.TraceIDRatioBased(cfg.SampleRate),
-				),
+				sdktrace.ParentBased(sampler),
 			),
 		)
 	} else {
@@ -70,24 +77,28 @@ func NewOpenTelemetryTracer(serviceName string, cfg *Config) trace.Tracer {
 	return &openTelemetryTracer{
 		traceExporter: traceExporter,
 		traceProvider: traceProvider,
+		sampler:       sampler,
 	}
 }
 
+func (t openTelemetryTracer) SetSampleRate(rate float64) {
+	t.sampler.setRate(rate)
+}
+
 func (t openTelemetryTracer) TracerProvider() trace.TracerProvider {
 	return t.traceProvider
 }
 
-func (t openTelemetryTracer) InitService() {
-	if t.traceExporter != nil {
-		err := t.traceExporter.Start(context.Background())
-		if err != nil {
-			panic(err)
-		}
+func (t openTelemetryTracer) InitService(ctx context.Context) error {
+	if t.traceExporter == nil {
+		return nil
 	}
+	return t.traceExporter.Start(ctx)
 }
 
-func (t openTelemetryTracer) StopService() {
-	if t.traceExporter != nil {
-		_ = t.traceExporter.Shutdown(context.Background())
+func (t openTelemetryTracer) StopService(ctx context.Context) error {
+	if t.traceExporter == nil {
+		return nil
 	}
+	return t.traceExporter.Shutdown(ctx)
 }
@@ -1,12 +1,15 @@
 package tracer
 
 import (
+	"github.com/N-Vokhmyanin/go-framework/application/config"
 	"github.com/N-Vokhmyanin/go-framework/contracts"
 	"github.com/N-Vokhmyanin/go-framework/cron"
 	"github.com/N-Vokhmyanin/go-framework/queue"
 	"github.com/N-Vokhmyanin/go-framework/tracer/trace"
 	"github.com/N-Vokhmyanin/go-framework/transport"
+	"github.com/N-Vokhmyanin/go-framework/utils/di"
 	_ "google.golang.org/grpc"
+	"strconv"
 )
 
 type provider struct {
@@ -14,6 +17,7 @@ type provider struct {
 }
 
 var _ contracts.Provider = (*provider)(nil)
+var _ contracts.ProviderWithOptionalDependencies = (*provider)(nil)
 
 func NewProvider() contracts.Provider {
 	return &provider{
@@ -22,9 +26,7 @@ func NewProvider() contracts.Provider {
 }
 
 func (p *provider) Config(c contracts.ConfigSet) {
-	c.BoolVar(&p.cfg.Enabled, "TRACER_ENABLED", false, "traces enabled")
-	c.StringVar(&p.cfg.Endpoint, "TRACER_ENDPOINT", "opentelemetry:4317", "opentelemetry grpc port")
-	c.Float64Var(&p.cfg.SampleRate, "TRACER_SAMPLE_RATE", 1.0, "traces sample rate")
+	config.Struct(c, p.cfg)
 }
 
 func (p *provider) Boot(a contracts.Application) {
@@ -37,24 +39,42 @@ func (p *provider) Boot(a contracts.Application) {
 	})
 }
 
+func (p *provider) After() []string {
+	return []string{
+		transport.TransportProviderName,
+		queue.AmqpProviderName,
+		cron.CronProviderName,
+	}
+}
+
 func (p *provider) Register(a contracts.Application) {
+	a.Make(func(cfg contracts.Config, tracer trace.Tracer) {
+		if setter, ok := tracer.(SampleRateSetter); ok {
+			cfg.Subscribe("TRACER_SAMPLE_RATE", func(value string) {
+				if rate, err := strconv.ParseFloat(value, 64); err == nil {
+					setter.SetSampleRate(rate)
+				}
+			})
+		}
+	})
+
 	a.Make(func(
 		tracer trace.Tracer,
-		httpGateway transport.HttpGateway,
-		grpcServer transport.GrpcServer,
-		queueManager queue.Manager,
-		cronService cron.Service,
+		optHttpGateway di.Optional[transport.HttpGateway],
+		optGrpcServer di.Optional[transport.GrpcServer],
+		optQueueManager di.Optional[queue.Manager],
+		optCronService di.Optional[cron.Service],
 	) {
 		tracerProvider := tracer.TracerProvider()
 
-		if httpGateway != nil {
+		if httpGateway, ok := optHttpGateway.Get(); ok {
 			httpGateway.WithOptions(
 				transport.WithMatchHeaders(propagators.Fields()...),
 				transport.WithServerMuxOptions(OutgoingHeaderMatcher),
 			)
 		}
 
-		if grpcServer != nil {
+		if grpcServer, ok := optGrpcServer.Get(); ok {
 			grpcServer.WithOptions(
 				transport.WithPrependUnaryInterceptors(
 					ContextTracerUnaryServerInterceptor(tracerProvider),
@@ -67,13 +87,13 @@ func (p *provider) Register(a contracts.Application) {
 			)
 		}
 
-		if queueManager != nil {
+		if queueManager, ok := optQueueManager.Get(); ok {
 			queueManager.Middleware(
 				ContextTracerQueueHandlerMiddleware(tracerProvider),
 			)
 		}
 
-		if cronService != nil {
+		if cronService, ok := optCronService.Get(); ok {
 			cronService.Middleware(
 				ContextTracerCronTaskMiddleware(tracerProvider),
 			)
@@ -0,0 +1,32 @@
+package tracer
+
+import (
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"sync/atomic"
+)
+
+// ratioSampler samples the ratio of traces, the ratio can be changed while traces are sampled.
+type ratioSampler struct {
+	sampler atomic.Pointer[sdktrace.Sampler]
+}
+
+var _ sdktrace.Sampler = (*ratioSampler)(nil)
+
+func newRatioSampler(rate float64) *ratioSampler {
+	s := &ratioSampler{}
+	s.setRate(rate)
+	return s
+}
+
+func (s *ratioSampler) setRate(rate float64) {
+	sampler := sdktrace.TraceIDRatioBased(rate)
+	s.sampler.Store(&sampler)
+}
+
+func (s *ratioSampler) ShouldSample(parameters sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return (*s.sampler.Load()).ShouldSample(parameters)
+}
+
+func (s *ratioSampler) Description() string {
+	return (*s.sampler.Load()).Description()
+}
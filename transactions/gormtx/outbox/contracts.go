@@ -0,0 +1,64 @@
+package outbox
+
+import (
+	"context"
+	"github.com/N-Vokhmyanin/go-framework/queue"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	KindJob   = "job"
+	KindEvent = "event"
+)
+
+// Outbox defers queue jobs and events until the transaction of the context is committed.
+type Outbox interface {
+	// Push writes the job to the outbox in the open transaction, without a transaction the job is pushed directly.
+	Push(ctx context.Context, job queue.Job, opts ...queue.JobOptionFunc) error
+	// Fire writes the event to the outbox in the open transaction, without a transaction the event is dispatched directly.
+	Fire(ctx context.Context, e interface{}) error
+	// Event registers the type of the event, only registered events can be fired through the outbox.
+	Event(e interface{})
+}
+
+// Aggregated is implemented by jobs and events which are published in the order they were written
+// with other messages of the same aggregate.
+type Aggregated interface {
+	Aggregate() string
+}
+
+// Relay publishes messages written to the outbox.
+type Relay interface {
+	// Notify wakes the relay up before its next poll.
+	Notify()
+}
+
+// Store keeps outbox messages.
+type Store interface {
+	// Migrate creates or updates the outbox table.
+	Migrate(ctx context.Context) error
+	// Add writes the message with the db of an open transaction.
+	Add(db *gorm.DB, message *Message) error
+	// Claim returns available messages ordered by id, each is the earliest pending message of its aggregate,
+	// the messages are hidden from other relays for the lock timeout.
+	Claim(ctx context.Context, limit int, lock time.Duration) ([]*Message, error)
+	// Complete deletes published messages and updates attempts of retried and failed ones.
+	Complete(ctx context.Context, published, retried []*Message) error
+}
+
+// Message is a job or an event waiting in the outbox.
+type Message struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement"`
+	Kind        string    `gorm:"size:16;not null"`
+	Name        string    `gorm:"size:255;not null"`
+	Aggregate   string    `gorm:"size:255;index"`
+	Payload     []byte    `gorm:"not null"`
+	Attempts    uint      `gorm:"not null;default:0"`
+	LastError   string    `gorm:"type:text"`
+	AvailableAt time.Time `gorm:"not null"`
+	// FailedAt is set once the message reaches the max attempts or can not be published at all,
+	// failed messages are kept for inspection and no longer block their aggregate.
+	FailedAt  *time.Time `gorm:"index"`
+	CreatedAt time.Time
+}
@@ -0,0 +1,182 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/errors"
+	"github.com/N-Vokhmyanin/go-framework/logger"
+	"github.com/N-Vokhmyanin/go-framework/queue"
+	"github.com/N-Vokhmyanin/go-framework/transactions/gormtx"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// EventJobName is the name of jobs which carry outbox events to the events queue.
+const EventJobName = "outbox.event"
+
+type outbox struct {
+	mu         sync.RWMutex
+	store      Store
+	relay      Relay
+	manager    queue.Manager
+	dispatcher contracts.Dispatcher
+	types      map[string]reflect.Type
+}
+
+var _ Outbox = (*outbox)(nil)
+
+func NewOutbox(store Store, relay Relay, manager queue.Manager, dispatcher contracts.Dispatcher) Outbox {
+	return &outbox{
+		store:      store,
+		relay:      relay,
+		manager:    manager,
+		dispatcher: dispatcher,
+		types:      make(map[string]reflect.Type),
+	}
+}
+
+func (o *outbox) Push(ctx context.Context, job queue.Job, opts ...queue.JobOptionFunc) error {
+	tx := gormtx.ExtractFromContext(ctx)
+	if tx == nil {
+		return o.manager.Push(ctx, job, opts...)
+	}
+
+	payload, err := queue.Encode(job, opts...)
+	if err != nil {
+		return errors.WrapWith(err, "encode job "+job.Name())
+	}
+	return o.add(tx, &Message{
+		Kind:      KindJob,
+		Name:      job.Name(),
+		Aggregate: aggregate(job),
+		Payload:   payload,
+	})
+}
+
+func (o *outbox) Fire(ctx context.Context, e interface{}) error {
+	tx := gormtx.ExtractFromContext(ctx)
+	if tx == nil {
+		return o.dispatcher.Dispatch(ctx, e)
+	}
+
+	name := eventName(reflect.TypeOf(e))
+	o.mu.RLock()
+	_, ok := o.types[name]
+	o.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("event %s is not registered in the outbox", name)
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return errors.WrapWith(err, "encode event "+name)
+	}
+	return o.add(tx, &Message{
+		Kind:      KindEvent,
+		Name:      name,
+		Aggregate: aggregate(e),
+		Payload:   payload,
+	})
+}
+
+func (o *outbox) Event(e interface{}) {
+	typ := reflect.TypeOf(e)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.types[eventName(typ)] = typ
+}
+
+// add writes the message in the transaction and wakes the relay up once it is committed.
+func (o *outbox) add(tx gormtx.Transaction, message *Message) error {
+	message.AvailableAt = time.Now()
+	if err := o.store.Add(tx.DB(), message); err != nil {
+		return errors.WrapWith(err, "write to outbox")
+	}
+	tx.OnSuccess(func(context.Context) {
+		o.relay.Notify()
+	})
+	return nil
+}
+
+// handler returns the handler dispatching events relayed to the events queue.
+func (o *outbox) handler(queueName string) queue.Handler {
+	return queue.SimpleHandler(
+		EventJobName,
+		queueName,
+		func(ctx context.Context, _ logger.Logger, i queue.JobInteract) error {
+			// events which can not be decoded fail on every attempt, so they are not requeued
+			var payload eventPayload
+			if err := i.Unmarshal(&payload); err != nil {
+				return i.Fail(err)
+			}
+			e, err := o.decode(payload)
+			if err != nil {
+				return i.Fail(err)
+			}
+			return o.dispatcher.Dispatch(ctx, e)
+		},
+	)
+}
+
+func (o *outbox) decode(payload eventPayload) (interface{}, error) {
+	o.mu.RLock()
+	typ, ok := o.types[payload.Type]
+	o.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("event %s is not registered in the outbox", payload.Type)
+	}
+
+	e := reflect.New(typ)
+	if err := json.Unmarshal(payload.Event, e.Interface()); err != nil {
+		return nil, errors.WrapWith(err, "decode event "+payload.Type)
+	}
+	return e.Elem().Interface(), nil
+}
+
+// eventPayload is the body of the job carrying an event.
+type eventPayload struct {
+	Type  string          `json:"type"`
+	Event json.RawMessage `json:"event"`
+}
+
+type eventJob struct {
+	queue   string
+	payload eventPayload
+}
+
+var _ queue.Job = (*eventJob)(nil)
+
+func (j *eventJob) Name() string {
+	return EventJobName
+}
+
+func (j *eventJob) Queue() string {
+	return j.queue
+}
+
+func (j *eventJob) Body() ([]byte, error) {
+	return json.Marshal(j.payload)
+}
+
+// eventName returns the package qualified name, so same-named types of different packages differ.
+func eventName(typ reflect.Type) string {
+	if typ.Kind() == reflect.Ptr {
+		return "*" + eventName(typ.Elem())
+	}
+	if typ.PkgPath() == "" {
+		return typ.String()
+	}
+	return typ.PkgPath() + "." + typ.Name()
+}
+
+func aggregate(v interface{}) string {
+	if a, ok := v.(Aggregated); ok {
+		return a.Aggregate()
+	}
+	return ""
+}
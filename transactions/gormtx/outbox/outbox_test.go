@@ -0,0 +1,93 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/logger"
+	"github.com/N-Vokhmyanin/go-framework/transactions/gormtx"
+	"github.com/N-Vokhmyanin/go-framework/transactions/gormtx/service"
+	"testing"
+)
+
+type testRelay struct {
+	notified int
+}
+
+func (r *testRelay) Notify() {
+	r.notified++
+}
+
+type testDispatcher struct {
+	contracts.Dispatcher
+	dispatched int
+}
+
+func (d *testDispatcher) Dispatch(context.Context, interface{}) error {
+	d.dispatched++
+	return nil
+}
+
+type testEvent struct {
+	ID int `json:"id"`
+}
+
+func Test_outbox_Transaction(t *testing.T) {
+	tests := []struct {
+		name       string
+		noTx       bool
+		endErr     error
+		rows       int64
+		notified   int
+		pushed     int
+		dispatched int
+	}{
+		{name: "committed messages are written", rows: 2, notified: 2},
+		{name: "rolled back messages are discarded", endErr: errors.New("failed")},
+		{name: "messages without transaction are published directly", noTx: true, pushed: 1, dispatched: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store, conn := newTestStore(t)
+			relay := &testRelay{}
+			manager := &testManager{}
+			dispatcher := &testDispatcher{}
+			o := NewOutbox(store, relay, manager, dispatcher)
+			o.Event(testEvent{})
+
+			txCtx := ctx
+			var tx gormtx.Transaction
+			if !tt.noTx {
+				tx, txCtx = service.NewService(conn, logger.GetNopLogger()).Start(ctx)
+			}
+
+			if err := o.Push(txCtx, testJob{name: "job"}); err != nil {
+				t.Fatalf("outbox.Push() error = %v", err)
+			}
+			if err := o.Fire(txCtx, testEvent{ID: 1}); err != nil {
+				t.Fatalf("outbox.Fire() error = %v", err)
+			}
+			if tx != nil {
+				if len(manager.pushed) != 0 || dispatcher.dispatched != 0 {
+					t.Fatalf("outbox published in the transaction: pushed %v, dispatched %d", manager.pushed, dispatcher.dispatched)
+				}
+				if err := tx.End(ctx, tt.endErr); !errors.Is(err, tt.endErr) {
+					t.Fatalf("transaction.End() error = %v, want %v", err, tt.endErr)
+				}
+			}
+
+			var rows int64
+			conn.DB().Table(DefaultTableName).Count(&rows)
+			if rows != tt.rows {
+				t.Errorf("outbox rows = %d, want %d", rows, tt.rows)
+			}
+			if relay.notified != tt.notified {
+				t.Errorf("relay notified %d times, want %d", relay.notified, tt.notified)
+			}
+			if len(manager.pushed) != tt.pushed || dispatcher.dispatched != tt.dispatched {
+				t.Errorf("outbox pushed %v, dispatched %d, want %d, %d", manager.pushed, dispatcher.dispatched, tt.pushed, tt.dispatched)
+			}
+		})
+	}
+}
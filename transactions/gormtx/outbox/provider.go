@@ -0,0 +1,86 @@
+package outbox
+
+import (
+	"github.com/N-Vokhmyanin/go-framework/application/config"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/database"
+	"github.com/N-Vokhmyanin/go-framework/errors"
+	"github.com/N-Vokhmyanin/go-framework/logger"
+	"github.com/N-Vokhmyanin/go-framework/queue"
+	gormtxProvider "github.com/N-Vokhmyanin/go-framework/transactions/gormtx/provider"
+	"time"
+)
+
+type provider struct {
+	table string
+	opts  RelayOptions
+}
+
+var _ contracts.ProviderWithDependencies = (*provider)(nil)
+var _ contracts.ConfigValidator = (*provider)(nil)
+
+//goland:noinspection GoUnusedExportedFunction
+func NewProvider() contracts.Provider {
+	return &provider{}
+}
+
+func (p *provider) Config(c contracts.ConfigSet) {
+	c.StringVar(&p.table, "OUTBOX_TABLE", DefaultTableName, "outbox table name")
+	c.DurationVar(&p.opts.Interval, "OUTBOX_POLL_INTERVAL", time.Second, "outbox polling interval")
+	c.IntVar(&p.opts.BatchSize, "OUTBOX_BATCH_SIZE", 100, "outbox messages published by a poll")
+	c.DurationVar(&p.opts.RetryDelay, "OUTBOX_RETRY_DELAY", time.Second, "delay after the first failed publish, doubled with every attempt")
+	c.DurationVar(&p.opts.RetryMaxDelay, "OUTBOX_RETRY_MAX_DELAY", 5*time.Minute, "max delay between publish attempts")
+	c.UintVar(&p.opts.MaxAttempts, "OUTBOX_MAX_ATTEMPTS", 10, "publish attempts before a message is marked as failed, 0 retries forever")
+	c.DurationVar(&p.opts.LockTimeout, "OUTBOX_LOCK_TIMEOUT", time.Minute, "time claimed messages are hidden from other relays")
+	c.StringVar(&p.opts.EventsQueue, "OUTBOX_EVENTS_QUEUE", "outbox-events", "queue outbox events are relayed through")
+	config.Rules(c, "OUTBOX_BATCH_SIZE", config.Min(1))
+}
+
+// ValidateConfig rejects non-positive durations and a max retry delay below the retry delay,
+// a zero delay would retry failed messages in a tight loop.
+func (p *provider) ValidateConfig(string) error {
+	errs := errors.NewMultiError()
+	if p.opts.Interval <= 0 {
+		errs.Append(errors.NewFieldValidationError("OUTBOX_POLL_INTERVAL", "must be positive"))
+	}
+	if p.opts.LockTimeout <= 0 {
+		errs.Append(errors.NewFieldValidationError("OUTBOX_LOCK_TIMEOUT", "must be positive"))
+	}
+	if p.opts.RetryDelay <= 0 {
+		errs.Append(errors.NewFieldValidationError("OUTBOX_RETRY_DELAY", "must be positive"))
+	}
+	if p.opts.RetryMaxDelay <= 0 {
+		errs.Append(errors.NewFieldValidationError("OUTBOX_RETRY_MAX_DELAY", "must be positive"))
+	} else if p.opts.RetryMaxDelay < p.opts.RetryDelay {
+		errs.Append(errors.NewFieldValidationError("OUTBOX_RETRY_MAX_DELAY", "must not be less than OUTBOX_RETRY_DELAY"))
+	}
+	return errs.ErrorOrNil()
+}
+
+func (p *provider) DependsOn() []string {
+	return []string{
+		gormtxProvider.ProviderName,
+		queue.AmqpProviderName,
+	}
+}
+
+func (p *provider) Boot(a contracts.Application) {
+	a.Singleton(func(conn database.Connection) Store {
+		return NewGormStore(conn, p.table)
+	})
+	a.Singleton(func(store Store, manager queue.Manager, log logger.Logger) *relay {
+		return NewRelay(store, manager, p.opts, log)
+	})
+	a.Singleton(func(store Store, r *relay, manager queue.Manager, dp contracts.Dispatcher) Outbox {
+		return NewOutbox(store, r, manager, dp)
+	})
+}
+
+func (p *provider) Register(a contracts.Application) {
+	a.Make(func(box Outbox, manager queue.Manager) {
+		manager.Queue(queue.SimpleQueue(p.opts.EventsQueue, 1))
+		if o, ok := box.(*outbox); ok {
+			manager.Handler(o.handler(p.opts.EventsQueue))
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package outbox
+
+import (
+	"github.com/N-Vokhmyanin/go-framework/errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_provider_ValidateConfig(t *testing.T) {
+	valid := RelayOptions{Interval: time.Second, LockTimeout: time.Minute, RetryDelay: time.Second, RetryMaxDelay: time.Minute}
+	tests := []struct {
+		name   string
+		modify func(o *RelayOptions)
+		want   []string
+	}{
+		{name: "valid", modify: func(*RelayOptions) {}},
+		{name: "zero max retry delay", modify: func(o *RelayOptions) { o.RetryMaxDelay = 0 }, want: []string{"OUTBOX_RETRY_MAX_DELAY"}},
+		{name: "zero retry delay", modify: func(o *RelayOptions) { o.RetryDelay = 0 }, want: []string{"OUTBOX_RETRY_DELAY"}},
+		{name: "max retry delay below retry delay", modify: func(o *RelayOptions) { o.RetryDelay = time.Hour }, want: []string{"OUTBOX_RETRY_MAX_DELAY"}},
+		{name: "zero lock timeout", modify: func(o *RelayOptions) { o.LockTimeout = 0 }, want: []string{"OUTBOX_LOCK_TIMEOUT"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &provider{opts: valid}
+			tt.modify(&p.opts)
+			var got []string
+			if err, ok := p.ValidateConfig("").(interface{ WrappedErrors() []error }); ok {
+				for _, fieldErr := range err.WrappedErrors() {
+					got = append(got, errors.AsErr[errors.FieldValidationError](fieldErr).Field())
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateConfig() fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
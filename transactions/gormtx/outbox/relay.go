@@ -0,0 +1,207 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/errors"
+	"github.com/N-Vokhmyanin/go-framework/logger"
+	"github.com/N-Vokhmyanin/go-framework/queue"
+	"go.uber.org/zap"
+	"time"
+)
+
+type RelayOptions struct {
+	// Interval between polls of the outbox.
+	Interval time.Duration
+	// BatchSize limits the number of messages read by a poll.
+	BatchSize int
+	// RetryDelay is the delay after the first failed attempt, it doubles with every next attempt.
+	RetryDelay time.Duration
+	// RetryMaxDelay limits the delay between attempts.
+	RetryMaxDelay time.Duration
+	// MaxAttempts limits publish attempts, then the message is marked as failed, zero retries forever.
+	MaxAttempts uint
+	// LockTimeout hides claimed messages from other relays, a message is claimed again after it,
+	// when the relay did not complete it.
+	LockTimeout time.Duration
+	// EventsQueue is the queue events are pushed to.
+	EventsQueue string
+}
+
+// relay publishes pending messages to the queue manager. A message which fails to publish is retried
+// with a growing delay, later messages of its aggregate wait for it, so each aggregate keeps its order.
+// Messages are published at least once: a message can be published again when deleting it fails.
+// Messages are published outside the claiming transaction, so row locks are held shortly.
+type relay struct {
+	store   Store
+	manager queue.Manager
+	opts    RelayOptions
+	log     logger.Logger
+	notify  chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+var _ Relay = (*relay)(nil)
+var _ contracts.CanStartContext = (*relay)(nil)
+var _ contracts.CanStopContext = (*relay)(nil)
+
+//goland:noinspection GoExportedFuncWithUnexportedType
+func NewRelay(store Store, manager queue.Manager, opts RelayOptions, log logger.Logger) *relay {
+	return &relay{
+		store:   store,
+		manager: manager,
+		opts:    opts,
+		log:     log.With(logger.WithComponent, "outbox"),
+		notify:  make(chan struct{}, 1),
+	}
+}
+
+func (r *relay) Notify() {
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (r *relay) StartService(ctx context.Context) error {
+	if err := r.store.Migrate(ctx); err != nil {
+		return fmt.Errorf("outbox migrate: %w", err)
+	}
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go r.run()
+	return nil
+}
+
+func (r *relay) StopService(ctx context.Context) error {
+	if r.stop == nil {
+		return nil
+	}
+	close(r.stop)
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *relay) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.opts.Interval)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-r.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		case <-r.notify:
+		}
+		// a claim returns one message per aggregate, so the outbox is polled until it is drained
+		for r.poll(ctx) > 0 && ctx.Err() == nil {
+		}
+	}
+}
+
+// poll publishes a batch of available messages and returns its size.
+func (r *relay) poll(ctx context.Context) int {
+	messages, err := r.store.Claim(ctx, r.opts.BatchSize, r.opts.LockTimeout)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.log.Errorw("outbox claim failed", zap.Error(err))
+		}
+		return 0
+	}
+
+	published, retried := r.publish(ctx, messages, time.Now())
+	// published messages are completed on stop too, otherwise they are published again
+	if err = r.store.Complete(context.WithoutCancel(ctx), published, retried); err != nil {
+		r.log.Errorw("outbox complete failed", zap.Error(err))
+		return 0
+	}
+	return len(messages)
+}
+
+// publish pushes the messages, a failed message is retried with a growing delay
+// until it reaches the max attempts or can not be published at all, then it is marked as failed.
+func (r *relay) publish(ctx context.Context, messages []*Message, now time.Time) (published, retried []*Message) {
+	for _, message := range messages {
+		err := r.push(ctx, message)
+		if err == nil {
+			published = append(published, message)
+			continue
+		}
+
+		message.Attempts++
+		message.LastError = err.Error()
+		message.AvailableAt = now.Add(r.backoff(message.Attempts))
+		retried = append(retried, message)
+
+		log := r.log.With(
+			"message.id", message.ID,
+			"message.name", message.Name,
+			"message.attempts", message.Attempts,
+		)
+		if errors.IsErr[*undeliverableErr](err) || (r.opts.MaxAttempts > 0 && message.Attempts >= r.opts.MaxAttempts) {
+			message.FailedAt = &now
+			log.Errorw("outbox message failed", zap.Error(err))
+			continue
+		}
+		log.Warnw("outbox publish failed", zap.Error(err))
+	}
+	return published, retried
+}
+
+func (r *relay) push(ctx context.Context, message *Message) error {
+	switch message.Kind {
+	case KindJob:
+		job, err := queue.Decode(message.Payload)
+		if err != nil {
+			return &undeliverableErr{err: err}
+		}
+		return r.manager.Push(ctx, job)
+	case KindEvent:
+		return r.manager.Push(ctx, &eventJob{
+			queue:   r.opts.EventsQueue,
+			payload: eventPayload{Type: message.Name, Event: message.Payload},
+		})
+	default:
+		return &undeliverableErr{err: fmt.Errorf("unknown outbox message kind %q", message.Kind)}
+	}
+}
+
+// backoff returns the delay before the next attempt.
+func (r *relay) backoff(attempts uint) time.Duration {
+	delay := r.opts.RetryDelay
+	for i := uint(1); i < attempts && delay < r.opts.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, r.opts.RetryMaxDelay)
+}
+
+// undeliverableErr reports a message which fails on every attempt, so it is not retried.
+type undeliverableErr struct {
+	err error
+}
+
+func (e *undeliverableErr) Error() string {
+	return e.err.Error()
+}
+
+func (e *undeliverableErr) Unwrap() error {
+	return e.err
+}
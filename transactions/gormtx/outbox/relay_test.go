@@ -0,0 +1,150 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/N-Vokhmyanin/go-framework/logger"
+	"github.com/N-Vokhmyanin/go-framework/queue"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testManager struct {
+	queue.Manager
+	failing map[string]bool
+	pushed  []string
+}
+
+func (m *testManager) Push(_ context.Context, job queue.Job, _ ...queue.JobOptionFunc) error {
+	if m.failing[job.Name()] {
+		return errors.New("push failed")
+	}
+	m.pushed = append(m.pushed, job.Name())
+	return nil
+}
+
+type testJob struct {
+	name string
+}
+
+func (j testJob) Name() string          { return j.name }
+func (j testJob) Queue() string         { return "default" }
+func (j testJob) Body() ([]byte, error) { return []byte("{}"), nil }
+
+func testMessage(t *testing.T, id uint64, name, aggregate string, availableAt time.Time) *Message {
+	payload, err := queue.Encode(testJob{name: name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Message{ID: id, Kind: KindJob, Name: name, Aggregate: aggregate, Payload: payload, AvailableAt: availableAt}
+}
+
+func Test_relay_publish(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		messages func(t *testing.T) []*Message
+		failing  map[string]bool
+		pushed   []string
+		retried  []uint64
+		failed   []uint64
+	}{
+		{
+			name: "publish in order",
+			messages: func(t *testing.T) []*Message {
+				return []*Message{
+					testMessage(t, 1, "a1", "a", now),
+					testMessage(t, 2, "b1", "b", now),
+				}
+			},
+			pushed: []string{"a1", "b1"},
+		},
+		{
+			name: "failed message is retried",
+			messages: func(t *testing.T) []*Message {
+				return []*Message{
+					testMessage(t, 1, "a1", "a", now),
+					testMessage(t, 2, "b1", "b", now),
+				}
+			},
+			failing: map[string]bool{"a1": true},
+			pushed:  []string{"b1"},
+			retried: []uint64{1},
+		},
+		{
+			name: "message reaching max attempts fails",
+			messages: func(t *testing.T) []*Message {
+				message := testMessage(t, 1, "a1", "a", now)
+				message.Attempts = 2
+				return []*Message{message}
+			},
+			failing: map[string]bool{"a1": true},
+			retried: []uint64{1},
+			failed:  []uint64{1},
+		},
+		{
+			name: "undeliverable message fails at once",
+			messages: func(t *testing.T) []*Message {
+				return []*Message{{ID: 1, Kind: "unknown", Name: "x1", AvailableAt: now}}
+			},
+			retried: []uint64{1},
+			failed:  []uint64{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := &testManager{failing: tt.failing}
+			r := NewRelay(nil, manager, RelayOptions{RetryDelay: time.Second, RetryMaxDelay: time.Minute, MaxAttempts: 3}, logger.GetNopLogger())
+
+			published, retried := r.publish(context.Background(), tt.messages(t), now)
+			if !reflect.DeepEqual(manager.pushed, tt.pushed) || len(published) != len(tt.pushed) {
+				t.Errorf("relay.publish() pushed %v, want %v", manager.pushed, tt.pushed)
+			}
+			var retriedIDs, failedIDs []uint64
+			for _, message := range retried {
+				retriedIDs = append(retriedIDs, message.ID)
+				if message.FailedAt != nil {
+					failedIDs = append(failedIDs, message.ID)
+				}
+				if !message.AvailableAt.Equal(now.Add(r.backoff(message.Attempts))) {
+					t.Errorf("relay.publish() retried %d with attempts %d at %s", message.ID, message.Attempts, message.AvailableAt)
+				}
+			}
+			if !reflect.DeepEqual(retriedIDs, tt.retried) {
+				t.Errorf("relay.publish() retried %v, want %v", retriedIDs, tt.retried)
+			}
+			if !reflect.DeepEqual(failedIDs, tt.failed) {
+				t.Errorf("relay.publish() failed %v, want %v", failedIDs, tt.failed)
+			}
+		})
+	}
+}
+
+func Test_relay_backoff(t *testing.T) {
+	r := NewRelay(nil, nil, RelayOptions{RetryDelay: time.Second, RetryMaxDelay: 10 * time.Second}, logger.GetNopLogger())
+	for attempts, want := range map[uint]time.Duration{1: time.Second, 2: 2 * time.Second, 4: 8 * time.Second, 10: 10 * time.Second} {
+		if got := r.backoff(attempts); got != want {
+			t.Errorf("relay.backoff(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
+
+func Test_outbox_decode(t *testing.T) {
+	type orderPaid struct {
+		ID int `json:"id"`
+	}
+	o := NewOutbox(nil, nil, nil, nil).(*outbox)
+	o.Event(orderPaid{})
+
+	payload := eventPayload{Type: eventName(reflect.TypeOf(orderPaid{})), Event: json.RawMessage(`{"id":42}`)}
+	got, err := o.decode(payload)
+	if err != nil || got != (orderPaid{ID: 42}) {
+		t.Errorf("outbox.decode() = %v, %v, want %v", got, err, orderPaid{ID: 42})
+	}
+
+	if _, err = o.decode(eventPayload{Type: "unknown", Event: json.RawMessage(`{}`)}); err == nil {
+		t.Errorf("outbox.decode() of unregistered event, want error")
+	}
+}
@@ -0,0 +1,98 @@
+package outbox
+
+import (
+	"context"
+	"github.com/N-Vokhmyanin/go-framework/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+const DefaultTableName = "outbox_messages"
+
+type gormStore struct {
+	conn  database.Connection
+	table string
+}
+
+var _ Store = (*gormStore)(nil)
+
+func NewGormStore(conn database.Connection, table string) Store {
+	if table == "" {
+		table = DefaultTableName
+	}
+	return &gormStore{
+		conn:  conn,
+		table: table,
+	}
+}
+
+func (s *gormStore) Migrate(ctx context.Context) error {
+	return s.conn.DB().WithContext(ctx).Table(s.table).AutoMigrate(&Message{})
+}
+
+func (s *gormStore) Add(db *gorm.DB, message *Message) error {
+	return db.Table(s.table).Create(message).Error
+}
+
+// Claim locks the messages with SKIP LOCKED and moves their availability past the lock timeout,
+// so the transaction ends before they are published and other relays skip them meanwhile.
+func (s *gormStore) Claim(ctx context.Context, limit int, lock time.Duration) ([]*Message, error) {
+	var messages []*Message
+	err := s.conn.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		earlier := tx.Session(&gorm.Session{NewDB: true}).
+			Table(s.table + " AS e").
+			Select("1").
+			Where("e.aggregate = m.aggregate AND e.id < m.id AND e.failed_at IS NULL")
+		err := tx.Table(s.table+" AS m").
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("m.failed_at IS NULL AND m.available_at <= ?", now).
+			Where("m.aggregate = '' OR NOT EXISTS (?)", earlier).
+			Order("m.id").
+			Limit(limit).
+			Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		ids := make([]uint64, len(messages))
+		for i, message := range messages {
+			ids[i] = message.ID
+		}
+		return tx.Table(s.table).Where("id IN ?", ids).Update("available_at", now.Add(lock)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (s *gormStore) Complete(ctx context.Context, published, retried []*Message) error {
+	if len(published) == 0 && len(retried) == 0 {
+		return nil
+	}
+	return s.conn.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(published) > 0 {
+			ids := make([]uint64, len(published))
+			for i, message := range published {
+				ids[i] = message.ID
+			}
+			if err := tx.Table(s.table).Where("id IN ?", ids).Delete(&Message{}).Error; err != nil {
+				return err
+			}
+		}
+		for _, message := range retried {
+			err := tx.Table(s.table).Where("id = ?", message.ID).Updates(map[string]interface{}{
+				"attempts":     message.Attempts,
+				"last_error":   message.LastError,
+				"available_at": message.AvailableAt,
+				"failed_at":    message.FailedAt,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,100 @@
+package outbox
+
+import (
+	"context"
+	"github.com/N-Vokhmyanin/go-framework/database"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testConnection struct {
+	db *gorm.DB
+}
+
+var _ database.Connection = (*testConnection)(nil)
+
+func (c *testConnection) DB() *gorm.DB               { return c.db }
+func (c *testConnection) Register(database.Callback) {}
+func (c *testConnection) IsConnected() bool          { return true }
+func (c *testConnection) Connect()                   {}
+func (c *testConnection) Close()                     {}
+
+// newTestStore returns a store over a sqlite database, it does not support row locks,
+// so claims are checked without concurrent relays.
+func newTestStore(t *testing.T) (Store, *testConnection) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "outbox.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := &testConnection{db: db}
+	store := NewGormStore(conn, "")
+	if err = store.Migrate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return store, conn
+}
+
+func messageNames(messages []*Message) []string {
+	var names []string
+	for _, message := range messages {
+		names = append(names, message.Name)
+	}
+	return names
+}
+
+func Test_gormStore_ClaimComplete(t *testing.T) {
+	ctx := context.Background()
+	store, conn := newTestStore(t)
+	now := time.Now()
+	failedAt := now.Add(-time.Minute)
+	for _, message := range []*Message{
+		{Kind: KindJob, Name: "failed", Aggregate: "a", Payload: []byte("{}"), AvailableAt: now, FailedAt: &failedAt},
+		{Kind: KindJob, Name: "a1", Aggregate: "a", Payload: []byte("{}"), AvailableAt: now},
+		{Kind: KindJob, Name: "a2", Aggregate: "a", Payload: []byte("{}"), AvailableAt: now},
+		{Kind: KindJob, Name: "b1", Aggregate: "b", Payload: []byte("{}"), AvailableAt: now.Add(time.Hour)},
+		{Kind: KindJob, Name: "b2", Aggregate: "b", Payload: []byte("{}"), AvailableAt: now},
+		{Kind: KindJob, Name: "x1", Payload: []byte("{}"), AvailableAt: now},
+		{Kind: KindJob, Name: "x2", Payload: []byte("{}"), AvailableAt: now},
+	} {
+		if err := store.Add(conn.DB(), message); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	claimed, err := store.Claim(ctx, 10, time.Minute)
+	if want := []string{"a1", "x1", "x2"}; err != nil || !reflect.DeepEqual(messageNames(claimed), want) {
+		t.Fatalf("gormStore.Claim() = %v, %v, want %v", messageNames(claimed), err, want)
+	}
+	if again, err := store.Claim(ctx, 10, time.Minute); err != nil || len(again) != 0 {
+		t.Fatalf("gormStore.Claim() of claimed messages = %v, %v, want none", messageNames(again), err)
+	}
+
+	retried := claimed[2]
+	retried.Attempts = 1
+	retried.LastError = "push failed"
+	retried.AvailableAt = now.Add(-time.Second)
+	if err = store.Complete(ctx, claimed[:2], []*Message{retried}); err != nil {
+		t.Fatalf("gormStore.Complete() error = %v", err)
+	}
+
+	claimed, err = store.Claim(ctx, 10, time.Minute)
+	if want := []string{"a2", "x2"}; err != nil || !reflect.DeepEqual(messageNames(claimed), want) {
+		t.Fatalf("gormStore.Claim() after complete = %v, %v, want %v", messageNames(claimed), err, want)
+	}
+	if claimed[1].Attempts != 1 || claimed[1].LastError != "push failed" {
+		t.Errorf("gormStore.Complete() retried message = %+v", claimed[1])
+	}
+
+	var count int64
+	conn.DB().Table(DefaultTableName).Count(&count)
+	if count != 5 {
+		t.Errorf("outbox rows = %d, want 5", count)
+	}
+}
@@ -2,21 +2,38 @@ package provider
 
 import (
 	fw "github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/database"
 	"github.com/N-Vokhmyanin/go-framework/transactions"
 	"github.com/N-Vokhmyanin/go-framework/transactions/gormtx"
 	"github.com/N-Vokhmyanin/go-framework/transactions/gormtx/service"
 )
 
+// ProviderName is the name other providers depend on the gorm transactions provider by.
+const ProviderName = "transactions.gormtx"
+
 type provider struct{}
 
+var _ fw.ProviderWithName = (*provider)(nil)
+var _ fw.ProviderWithDependencies = (*provider)(nil)
+
 func New() fw.Provider {
 	return &provider{}
 }
 
+func (p *provider) ProviderName() string {
+	return ProviderName
+}
+
 func (p *provider) Config(_ fw.ConfigSet) {
 	// nothing
 }
 
+func (p *provider) DependsOn() []string {
+	return []string{
+		database.GormProviderName,
+	}
+}
+
 func (p *provider) Boot(a fw.Application) {
 	a.Singleton(service.NewService)
 	a.Singleton(func(svc gormtx.Service) transactions.Service {
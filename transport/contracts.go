@@ -11,8 +11,12 @@ type RegisterHttpHandler func(m *runtime.ServeMux, c *grpc.ClientConn)
 type GrpcServer interface {
 	WithOptions(options ...GrpcOption)
 	GetServiceInfo() map[string]grpc.ServiceInfo
+	// Addr returns the listening address once started, the configured one otherwise.
+	Addr() string
 }
 
 type HttpGateway interface {
 	WithOptions(options ...HttpOption)
+	// Addr returns the listening address once started, the configured one otherwise.
+	Addr() string
 }
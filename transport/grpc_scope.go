@@ -0,0 +1,36 @@
+package transport
+
+import (
+	"context"
+	"github.com/N-Vokhmyanin/go-framework/application/ctxapp"
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/logger/grpc/ctxlog"
+	grpcMiddleware "github.com/grpc-ecosystem/go-grpc-middleware/v2"
+	"google.golang.org/grpc"
+)
+
+// ScopeUnaryServerInterceptor resolves scoped bindings once per call and disposes them when the call ends.
+func ScopeUnaryServerInterceptor(c contracts.Container) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		scope := c.NewScope()
+		defer closeScope(ctx, scope)
+		return handler(ctxapp.Inject(ctx, scope), req)
+	}
+}
+
+// ScopeStreamServerInterceptor resolves scoped bindings once per stream and disposes them when the stream ends.
+func ScopeStreamServerInterceptor(c contracts.Container) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		scope := c.NewScope()
+		defer closeScope(ss.Context(), scope)
+		wrapped := grpcMiddleware.WrapServerStream(ss)
+		wrapped.WrappedContext = ctxapp.Inject(ss.Context(), scope)
+		return handler(srv, wrapped)
+	}
+}
+
+func closeScope(ctx context.Context, scope contracts.Scope) {
+	if err := scope.Close(); err != nil {
+		ctxlog.Extract(ctx).Errorw("close scope", "error", err)
+	}
+}
@@ -16,8 +16,9 @@ import (
 )
 
 type grpcServer struct {
-	addr string
-	log  logger.Logger
+	addr       string
+	listenAddr string
+	log        logger.Logger
 
 	options *grpcOptions
 	server  *grpc.Server
@@ -28,8 +29,8 @@ type grpcServer struct {
 var _ GrpcServer = (*grpcServer)(nil)
 var _ health.Service = (*grpcServer)(nil)
 var _ contracts.CanBoot = (*grpcServer)(nil)
-var _ contracts.CanStart = (*grpcServer)(nil)
-var _ contracts.CanStop = (*grpcServer)(nil)
+var _ contracts.CanStartContext = (*grpcServer)(nil)
+var _ contracts.CanStopContext = (*grpcServer)(nil)
 
 func NewGrpcServer(addr string, log logger.Logger) GrpcServer {
 	srv := &grpcServer{
@@ -55,6 +56,13 @@ func (s *grpcServer) GetServiceInfo() map[string]grpc.ServiceInfo {
 	return s.server.GetServiceInfo()
 }
 
+func (s *grpcServer) Addr() string {
+	if s.listenAddr != "" {
+		return s.listenAddr
+	}
+	return s.addr
+}
+
 func (s *grpcServer) WithOptions(options ...GrpcOption) {
 	for _, opt := range options {
 		opt(s.options)
@@ -105,23 +113,41 @@ func (s *grpcServer) BootService() {
 	}
 }
 
-func (s *grpcServer) StartService() {
-	if listener, err := net.Listen("tcp", s.addr); err != nil {
-		s.log.Fatalw("listen addr failed", "addr", s.addr, zap.Error(err))
-	} else {
-		s.log.Infow("starting server...", "addr", listener.Addr().String())
-		go func() {
-			s.serving = true
-			if err = s.server.Serve(listener); err != nil {
-				s.log.Fatal(err)
-			}
-		}()
+func (s *grpcServer) StartService(ctx context.Context) error {
+	listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", s.addr)
+	if err != nil {
+		return errors.WrapWith(err, "listen addr %s failed", s.addr)
 	}
+	s.listenAddr = listener.Addr().String()
+
+	s.log.Infow("starting server...", "addr", s.listenAddr)
+	go func() {
+		s.serving = true
+		if err = s.server.Serve(listener); err != nil {
+			s.log.Errorw("serve failed", zap.Error(err))
+		}
+		s.serving = false
+	}()
+	return nil
 }
 
-func (s *grpcServer) StopService() {
+func (s *grpcServer) StopService(ctx context.Context) error {
 	s.serving = false
-	if s.server != nil {
+	if s.server == nil {
+		return nil
+	}
+
+	stopped := make(chan struct{})
+	go func() {
 		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
 	}
 }
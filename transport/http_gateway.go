@@ -3,6 +3,7 @@ package transport
 import (
 	"context"
 	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/errors"
 	health "github.com/N-Vokhmyanin/go-framework/health/contracts"
 	"github.com/N-Vokhmyanin/go-framework/logger"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
@@ -18,12 +19,14 @@ import (
 )
 
 type httpGateway struct {
-	addr string
-	grpc string
-	log  logger.Logger
+	addr       string
+	listenAddr string
+	grpc       string
+	log        logger.Logger
 
 	options *httpOptions
 	grpcMux *runtime.ServeMux
+	server  *http.Server
 
 	serving bool
 }
@@ -31,8 +34,8 @@ type httpGateway struct {
 var _ HttpGateway = (*httpGateway)(nil)
 var _ health.Service = (*httpGateway)(nil)
 var _ contracts.CanBoot = (*httpGateway)(nil)
-var _ contracts.CanStart = (*httpGateway)(nil)
-var _ contracts.CanStop = (*httpGateway)(nil)
+var _ contracts.CanStartContext = (*httpGateway)(nil)
+var _ contracts.CanStopContext = (*httpGateway)(nil)
 
 func NewHttpServer(addr, grpc string, log logger.Logger) HttpGateway {
 	gw := &httpGateway{
@@ -72,6 +75,13 @@ func (s *httpGateway) WithOptions(options ...HttpOption) {
 	}
 }
 
+func (s *httpGateway) Addr() string {
+	if s.listenAddr != "" {
+		return s.listenAddr
+	}
+	return s.addr
+}
+
 func (s *httpGateway) HealthStatus(context.Context) grpcHealthV1.HealthCheckResponse_ServingStatus {
 	return health.HealthStatusFromBool(s.serving)
 }
@@ -80,20 +90,24 @@ func (s *httpGateway) BootService() {
 	s.grpcMux = runtime.NewServeMux(s.options.serverMuxOptions...)
 }
 
-func (s *httpGateway) StartService() {
-	ctx := context.Background()
-
-	listener, err := net.Listen("tcp", s.addr)
+func (s *httpGateway) StartService(ctx context.Context) error {
+	listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", s.addr)
 	if err != nil {
-		s.log.Fatalw("listen addr failed", "addr", s.addr, zap.Error(err))
+		return errors.WrapWith(err, "listen addr %s failed", s.addr)
 	}
+	s.listenAddr = listener.Addr().String()
 
+	target := s.grpc
+	if s.options.grpcServer != nil {
+		target = s.options.grpcServer.Addr()
+	}
 	dialOpts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 	}
-	conn, err := grpc.NewClient(s.grpc /*grpc.WithBlock(),*/, dialOpts...)
+	conn, err := grpc.NewClient(target /*grpc.WithBlock(),*/, dialOpts...)
 	if err != nil {
-		s.log.Fatalw("dial context failed", "target", s.grpc, zap.Error(err))
+		_ = listener.Close()
+		return errors.WrapWith(err, "dial %s failed", target)
 	}
 
 	for _, registerHandler := range s.options.registerHandlers {
@@ -101,7 +115,8 @@ func (s *httpGateway) StartService() {
 	}
 	for _, h := range s.options.httpHandlers {
 		if err = s.grpcMux.HandlePath(h.method, h.pattern, h.HandlerFunc(s.grpcMux)); err != nil {
-			s.log.Fatalw("register http handler failed", "pattern", h.pattern, zap.Error(err))
+			_ = listener.Close()
+			return errors.WrapWith(err, "register http handler %s failed", h.pattern)
 		}
 	}
 
@@ -109,18 +124,27 @@ func (s *httpGateway) StartService() {
 	for _, middleware := range s.options.httpMiddlewares {
 		handler = middleware(handler)
 	}
+	s.server = &http.Server{Handler: handler}
 
-	s.log.Infow("starting server...", "addr", listener.Addr().String())
+	s.log.Infow("starting server...", "addr", s.listenAddr)
 	go func() {
-		if conn.WaitForStateChange(ctx, connectivity.Idle) {
+		// the client stays idle until it is asked to connect
+		conn.Connect()
+		if conn.WaitForStateChange(context.Background(), connectivity.Idle) {
 			s.serving = true
 		}
-		if err = http.Serve(listener, handler); err != nil {
-			s.log.Fatal(err)
+		if err = s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.log.Errorw("serve failed", zap.Error(err))
 		}
+		s.serving = false
 	}()
+	return nil
 }
 
-func (s *httpGateway) StopService() {
+func (s *httpGateway) StopService(ctx context.Context) error {
 	s.serving = false
+	if s.server == nil {
+		return nil
+	}
+	return s.server.Shutdown(ctx)
 }
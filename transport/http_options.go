@@ -11,10 +11,20 @@ type httpOptions struct {
 	httpMiddlewares  []func(http.Handler) http.Handler
 	serverMuxOptions []runtime.ServeMuxOption
 	registerHandlers []RegisterHttpHandler
+	grpcServer       GrpcServer
 }
 
 type HttpOption func(o *httpOptions)
 
+// WithGrpcServer makes the gateway dial the address the grpc server listens on once started.
+//
+//goland:noinspection GoUnusedExportedFunction
+func WithGrpcServer(server GrpcServer) HttpOption {
+	return func(o *httpOptions) {
+		o.grpcServer = server
+	}
+}
+
 //goland:noinspection GoUnusedExportedFunction
 func WithMatchHeaders(headers ...string) HttpOption {
 	return func(o *httpOptions) {
@@ -2,23 +2,45 @@ package transport
 
 import (
 	"fmt"
+	"github.com/N-Vokhmyanin/go-framework/application/config"
 	"github.com/N-Vokhmyanin/go-framework/contracts"
+	"github.com/N-Vokhmyanin/go-framework/errors"
 	"github.com/N-Vokhmyanin/go-framework/logger"
 )
 
+// TransportProviderName is the name other providers depend on the transport provider by.
+const TransportProviderName = "transport"
+
 type transportProvider struct {
 	portHttp uint
 	portGrpc uint
 }
 
+var _ contracts.ConfigValidator = (*transportProvider)(nil)
+var _ contracts.ProviderWithName = (*transportProvider)(nil)
+
 //goland:noinspection GoUnusedExportedFunction
 func NewTransportProvider() contracts.Provider {
 	return &transportProvider{}
 }
 
+func (p *transportProvider) ProviderName() string {
+	return TransportProviderName
+}
+
 func (p *transportProvider) Config(c contracts.ConfigSet) {
 	c.UintVar(&p.portHttp, "SERVER_HTTP_PORT", 8080, "http listener port")
 	c.UintVar(&p.portGrpc, "SERVER_GRPC_PORT", 8090, "grpc listener port")
+	config.Rules(c, "SERVER_HTTP_PORT", config.Max(65535))
+	config.Rules(c, "SERVER_GRPC_PORT", config.Max(65535))
+}
+
+// ValidateConfig checks the listeners do not share a port, zero ports are chosen by the system.
+func (p *transportProvider) ValidateConfig(string) error {
+	if p.portHttp != 0 && p.portHttp == p.portGrpc {
+		return errors.NewFieldValidationError("SERVER_HTTP_PORT", "port %d is already used by SERVER_GRPC_PORT", p.portHttp)
+	}
+	return nil
 }
 
 func (p *transportProvider) Boot(a contracts.Application) {
@@ -29,13 +51,19 @@ func (p *transportProvider) Boot(a contracts.Application) {
 		return NewGrpcServer(grpcAddr, log)
 	})
 
-	a.Singleton(func(log logger.Logger) HttpGateway {
-		return NewHttpServer(httpAddr, grpcAddr, log)
+	a.Singleton(func(log logger.Logger, grpc GrpcServer) HttpGateway {
+		gateway := NewHttpServer(httpAddr, grpcAddr, log)
+		gateway.WithOptions(WithGrpcServer(grpc))
+		return gateway
 	})
 }
 
 func (p *transportProvider) Register(a contracts.Application) {
 	a.Make(func(log logger.Logger, grpc GrpcServer) {
+		grpc.WithOptions(
+			WithPrependUnaryInterceptors(ScopeUnaryServerInterceptor(a)),
+			WithPrependStreamInterceptors(ScopeStreamServerInterceptor(a)),
+		)
 		a.Command(NewRpcListCommand(grpc))
 	})
 }
@@ -33,3 +33,46 @@ func AsSingleton[IN any](a contracts.Container, in IN) {
 		return in
 	})
 }
+
+func GetNamed[T any](c contracts.Container, name string) (out T) {
+	c.MakeNamed(name, &out)
+	return out
+}
+
+// Tagged returns instances registered with the tag which implement T.
+func Tagged[T any](c contracts.Container, tag string) (out []T) {
+	for _, instance := range c.MakeTagged(tag) {
+		if v, ok := instance.(T); ok {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// All returns singleton instances which implement T in dependency order.
+func All[T any](c contracts.Container) (out []T) {
+	for _, instance := range c.Instances() {
+		if v, ok := instance.(T); ok {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func AsSingletonNamed[IN any](a contracts.Container, name string, in IN) {
+	a.SingletonNamed(name, func() IN {
+		return in
+	})
+}
+
+// New builds T by field injection, T is a struct or a pointer to a struct.
+func New[T any](c contracts.Container) (out T) {
+	typ := reflect.TypeOf(&out).Elem()
+	if typ.Kind() == reflect.Ptr {
+		value := reflect.New(typ.Elem())
+		c.Inject(value.Interface())
+		return value.Interface().(T)
+	}
+	c.Inject(&out)
+	return out
+}
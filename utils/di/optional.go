@@ -0,0 +1,48 @@
+package di
+
+import (
+	"reflect"
+
+	"github.com/N-Vokhmyanin/go-framework/contracts"
+)
+
+// Optional marks a resolver argument as an optional dependency.
+// Arguments of other types must have a binding or the resolution fails.
+type Optional[T any] struct {
+	value T
+	ok    bool
+}
+
+var _ contracts.OptionalDependency = Optional[any]{}
+var _ contracts.OptionalDependencySetter = (*Optional[any])(nil)
+
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{value: value, ok: true}
+}
+
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.ok
+}
+
+func (o Optional[T]) Value() T {
+	return o.value
+}
+
+func (o Optional[T]) Ok() bool {
+	return o.ok
+}
+
+func (o Optional[T]) DependencyType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (o *Optional[T]) SetDependency(value any) {
+	o.value, o.ok = value.(T)
+}
+
+// Maybe resolves T if it is bound.
+func Maybe[T any](c contracts.Container) Optional[T] {
+	return Make[Optional[T]](c, func(v Optional[T]) Optional[T] {
+		return v
+	})
+}